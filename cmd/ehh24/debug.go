@@ -0,0 +1,44 @@
+//go:build debug
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/kettek/ehh24/pkg/editor"
+	"github.com/kettek/ehh24/pkg/game"
+	"github.com/kettek/ehh24/pkg/intro"
+	"github.com/kettek/ehh24/pkg/res"
+	"github.com/kettek/ehh24/pkg/splash"
+	"github.com/kettek/ehh24/pkg/statemachine"
+	"github.com/kettek/ehh24/pkg/title"
+)
+
+// addDebugKeys lets the F keys jump straight to states. The game and intro are put over the title, as they would be normally.
+func addDebugKeys(m *statemachine.Machine, loader *res.Loader) {
+	m.AddCheck(func() {
+		if !loader.Done() {
+			return
+		}
+		if inpututil.IsKeyJustReleased(ebiten.KeyF1) {
+			m.SetState(title.NewState())
+			m.Push(game.NewState(), nil)
+		} else if inpututil.IsKeyJustReleased(ebiten.KeyF2) {
+			m.SetState(editor.NewState())
+		} else if inpututil.IsKeyJustReleased(ebiten.KeyF3) {
+			m.SetState(splash.NewState())
+		} else if inpututil.IsKeyJustReleased(ebiten.KeyF4) {
+			m.SetState(title.NewState())
+			m.Push(intro.NewState(), nil)
+		}
+	})
+}
+
+// logSources prints where assets are read from, in order.
+func logSources() {
+	for _, src := range res.Sources() {
+		fmt.Println("asset source:", src.Name)
+	}
+}
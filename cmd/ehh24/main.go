@@ -1,38 +1,45 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
-	"github.com/kettek/ehh24/pkg/editor"
-	"github.com/kettek/ehh24/pkg/game"
-	"github.com/kettek/ehh24/pkg/intro"
+	"github.com/kettek/ehh24/pkg/audio"
+	"github.com/kettek/ehh24/pkg/config"
+	"github.com/kettek/ehh24/pkg/loading"
 	"github.com/kettek/ehh24/pkg/res"
 	"github.com/kettek/ehh24/pkg/splash"
 	"github.com/kettek/ehh24/pkg/statemachine"
+	"github.com/kettek/ehh24/pkg/transition"
 )
 
 func main() {
-	if err := res.ReadAssets(); err != nil {
+	if err := res.AddMods("mods"); err != nil {
 		panic(err)
 	}
+	logSources()
 
 	ebiten.SetWindowSize(1280, 720)
 	ebiten.SetWindowTitle("Hello, 世界")
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+
+	if err := config.Load(); err != nil {
+		fmt.Println("couldn't load settings", err)
+	}
+	config.Apply()
 
-	m := statemachine.NewMachine(splash.NewState())
-	m.AddCheck(func() {
-		if inpututil.IsKeyJustReleased(ebiten.KeyF1) {
-			m.SetState(game.NewState())
-		} else if inpututil.IsKeyJustReleased(ebiten.KeyF2) {
-			m.SetState(editor.NewState())
-		} else if inpututil.IsKeyJustReleased(ebiten.KeyF3) {
-			m.SetState(splash.NewState())
-		} else if inpututil.IsKeyJustReleased(ebiten.KeyF4) {
-			m.SetState(intro.NewState())
-		}
-	})
+	loader := res.LoadAssets()
+	m := statemachine.NewMachine(loading.NewState(loader, func() statemachine.State {
+		return statemachine.WithTransition(splash.NewState(), transition.New(transition.KindFade, 100))
+	}))
+	m.AddCheck(audio.Update)
+	addDebugKeys(m, loader)
 
-	if err := ebiten.RunGame(m); err != nil {
+	err := ebiten.RunGame(m)
+	if err := res.CloseSources(); err != nil {
+		fmt.Println("couldn't close asset sources", err)
+	}
+	if err != nil {
 		panic(err)
 	}
 }
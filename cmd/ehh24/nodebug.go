@@ -0,0 +1,14 @@
+//go:build !debug
+
+package main
+
+import (
+	"github.com/kettek/ehh24/pkg/res"
+	"github.com/kettek/ehh24/pkg/statemachine"
+)
+
+// addDebugKeys does nothing outside of debug builds. Build with -tags debug for the F key shortcuts.
+func addDebugKeys(m *statemachine.Machine, loader *res.Loader) {}
+
+// logSources does nothing outside of debug builds.
+func logSources() {}
@@ -0,0 +1,98 @@
+// Package audio plays our music, ambience, and sound effects. Sounds are read through res and decoded the first time they are played.
+package audio
+
+import (
+	"math"
+
+	ebiaudio "github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+// SampleRate is the rate everything is decoded and played at.
+const SampleRate = 44100
+
+// Bus is a group of sounds that share a volume.
+type Bus int
+
+// Our buses. Master scales all of the others.
+const (
+	BusMaster Bus = iota
+	BusMusic
+	BusAmbience
+	BusSFX
+)
+
+// Buses is every bus, in order.
+var Buses = []Bus{BusMaster, BusMusic, BusAmbience, BusSFX}
+
+// String returns the string representation of a Bus.
+func (b Bus) String() string {
+	switch b {
+	case BusMaster:
+		return "Master"
+	case BusMusic:
+		return "Music"
+	case BusAmbience:
+		return "Ambience"
+	case BusSFX:
+		return "SFX"
+	}
+	return "Unknown"
+}
+
+var (
+	context              *ebiaudio.Context
+	volumes              = map[Bus]float64{BusMaster: 1, BusMusic: 0.6, BusAmbience: 0.8, BusSFX: 1}
+	muted                bool
+	listenerX, listenerY float64
+)
+
+// getContext returns the audio context, making it if need be. Ebiten only allows one.
+func getContext() *ebiaudio.Context {
+	if context == nil {
+		context = ebiaudio.NewContext(SampleRate)
+	}
+	return context
+}
+
+// Volume returns the volume of a bus, from 0 to 1.
+func Volume(bus Bus) float64 {
+	return volumes[bus]
+}
+
+// SetVolume sets the volume of a bus, from 0 to 1.
+func SetVolume(bus Bus, volume float64) {
+	volumes[bus] = math.Max(0, math.Min(1, volume))
+}
+
+// Muted returns if all audio is muted.
+func Muted() bool {
+	return muted
+}
+
+// SetMuted mutes or unmutes all audio. Everything keeps playing, just silently.
+func SetMuted(m bool) {
+	muted = m
+}
+
+// gain returns the volume a bus actually plays at.
+func gain(bus Bus) float64 {
+	if muted {
+		return 0
+	}
+	if bus == BusMaster {
+		return volumes[BusMaster]
+	}
+	return volumes[BusMaster] * volumes[bus]
+}
+
+// SetListener sets where positional sounds are heard from, usually the player.
+func SetListener(x, y float64) {
+	listenerX, listenerY = x, y
+}
+
+// Update fades music and ambience, follows the listener with positional sounds, and cleans up finished ones. It should be called every tick.
+func Update() {
+	music.update()
+	ambience.update()
+	updateSounds()
+}
@@ -0,0 +1,48 @@
+package audio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+	"github.com/kettek/ehh24/pkg/res"
+)
+
+// clip is a decoded sound, as 16-bit stereo PCM at our sample rate.
+type clip struct {
+	src []byte // The res data it was decoded from, so reloaded sounds are decoded again.
+	pcm []byte
+}
+
+// clips is a cache of our decoded sounds.
+var clips = make(map[string]*clip)
+
+// getClip returns the decoded PCM of the named sound.
+func getClip(name string) ([]byte, error) {
+	snd, ok := res.Sounds[name]
+	if !ok || len(snd.Data) == 0 {
+		return nil, fmt.Errorf("sound %q not found", name)
+	}
+	if c, ok := clips[name]; ok && &c.src[0] == &snd.Data[0] {
+		return c.pcm, nil
+	}
+
+	var stream io.Reader
+	var err error
+	if snd.Ogg {
+		stream, err = vorbis.DecodeWithSampleRate(SampleRate, bytes.NewReader(snd.Data))
+	} else {
+		stream, err = wav.DecodeWithSampleRate(SampleRate, bytes.NewReader(snd.Data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sound %q: %w", name, err)
+	}
+	pcm, err := io.ReadAll(stream)
+	if err != nil {
+		return nil, fmt.Errorf("sound %q: %w", name, err)
+	}
+	clips[name] = &clip{src: snd.Data, pcm: pcm}
+	return pcm, nil
+}
@@ -0,0 +1,120 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sync/atomic"
+
+	ebiaudio "github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+// Positional sound limits and such.
+const (
+	hearingRange = 320 // Distance at which positional sounds fall silent.
+	panRange     = 160 // Sideways distance at which positional sounds are entirely in one ear.
+	maxSounds    = 32  // Most sound effects playing at once. Any more are dropped.
+)
+
+// sound is a playing sound effect.
+type sound struct {
+	player     *ebiaudio.Player
+	panner     *panner
+	positional bool
+	x, y       float64
+}
+
+// sounds are the sound effects currently playing.
+var sounds []*sound
+
+// Play plays a sound effect as is.
+func Play(name string) {
+	play(name, false, 0, 0)
+}
+
+// PlayAt plays a sound effect from a point in za warudo, panned and quietened by where it is from the listener.
+func PlayAt(name string, x, y float64) {
+	play(name, true, x, y)
+}
+
+// play starts a sound effect.
+func play(name string, positional bool, x, y float64) {
+	if len(sounds) >= maxSounds {
+		return
+	}
+	pcm, err := getClip(name)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	s := &sound{
+		panner:     &panner{src: pcm},
+		positional: positional,
+		x:          x,
+		y:          y,
+	}
+	if s.player, err = getContext().NewPlayer(s.panner); err != nil {
+		fmt.Println(err)
+		return
+	}
+	s.update()
+	s.player.Play()
+	sounds = append(sounds, s)
+}
+
+// update sets the sound's volume and pan from where it is relative to the listener.
+func (s *sound) update() {
+	volume := gain(BusSFX)
+	pan := 0.0
+	if s.positional {
+		dx, dy := s.x-listenerX, s.y-listenerY
+		volume *= math.Max(0, 1-math.Hypot(dx, dy)/hearingRange)
+		pan = math.Max(-1, math.Min(1, dx/panRange))
+	}
+	s.player.SetVolume(volume)
+	s.panner.pan.Store(math.Float64bits(pan))
+}
+
+// updateSounds updates playing sounds and closes finished ones.
+func updateSounds() {
+	sounds = slices.DeleteFunc(sounds, func(s *sound) bool {
+		if !s.player.IsPlaying() {
+			s.player.Close()
+			return true
+		}
+		s.update()
+		return false
+	})
+}
+
+// panner streams 16-bit stereo PCM, turning down one side or the other. Ebiten players have no pan of their own.
+type panner struct {
+	src []byte
+	pos int
+	pan atomic.Uint64 // Float64 bits, from -1 for left to 1 for right. Set from the game, read from the audio goroutine.
+}
+
+// Read reads panned PCM.
+func (p *panner) Read(buf []byte) (int, error) {
+	if p.pos >= len(p.src) {
+		return 0, io.EOF
+	}
+	n := copy(buf[:len(buf)/4*4], p.src[p.pos:])
+	p.pos += n
+
+	pan := math.Float64frombits(p.pan.Load())
+	if pan == 0 {
+		return n, nil
+	}
+	left := math.Min(1, 1-pan)
+	right := math.Min(1, 1+pan)
+	for i := 0; i+4 <= n; i += 4 {
+		l := int16(binary.LittleEndian.Uint16(buf[i:]))
+		r := int16(binary.LittleEndian.Uint16(buf[i+2:]))
+		binary.LittleEndian.PutUint16(buf[i:], uint16(int16(float64(l)*left)))
+		binary.LittleEndian.PutUint16(buf[i+2:], uint16(int16(float64(r)*right)))
+	}
+	return n, nil
+}
@@ -0,0 +1,93 @@
+package audio
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"slices"
+
+	ebiaudio "github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+// fadeTicks is how long music and ambience take to fade in or out.
+const fadeTicks = 90
+
+// channel plays one looping track at a time, crossfading from the old to the new whenever it changes.
+type channel struct {
+	bus     Bus
+	current *track
+	fading  []*track
+}
+
+// track is a looping sound and how far faded in it is.
+type track struct {
+	name   string
+	player *ebiaudio.Player // Nil if the sound couldn't be played, so we don't keep trying.
+	level  float64
+}
+
+var (
+	music    = channel{bus: BusMusic}
+	ambience = channel{bus: BusAmbience}
+)
+
+// PlayMusic crossfades to the named music. Playing what is already playing does nothing, and an empty name fades out.
+func PlayMusic(name string) {
+	music.play(name)
+}
+
+// PlayAmbience crossfades to the named ambience loop. Playing what is already playing does nothing, and an empty name fades out.
+func PlayAmbience(name string) {
+	ambience.play(name)
+}
+
+// play switches to the named track, fading out the current one.
+func (c *channel) play(name string) {
+	if c.current != nil {
+		if c.current.name == name {
+			return
+		}
+		c.fading = append(c.fading, c.current)
+		c.current = nil
+	}
+	if name == "" {
+		return
+	}
+
+	c.current = &track{name: name}
+	pcm, err := getClip(name)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	player, err := getContext().NewPlayer(ebiaudio.NewInfiniteLoop(bytes.NewReader(pcm), int64(len(pcm))))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	player.SetVolume(0)
+	player.Play()
+	c.current.player = player
+}
+
+// update fades the current track in and the old ones out, closing them once silent.
+func (c *channel) update() {
+	step := 1.0 / fadeTicks
+	volume := gain(c.bus)
+	if t := c.current; t != nil && t.player != nil {
+		t.level = math.Min(1, t.level+step)
+		t.player.SetVolume(t.level * volume)
+	}
+	c.fading = slices.DeleteFunc(c.fading, func(t *track) bool {
+		if t.player == nil {
+			return true
+		}
+		t.level -= step
+		if t.level <= 0 {
+			t.player.Close()
+			return true
+		}
+		t.player.SetVolume(t.level * volume)
+		return false
+	})
+}
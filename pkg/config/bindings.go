@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+
+	input "github.com/quasilyte/ebitengine-input"
+)
+
+// Binding is an action that can be rebound, with the keys it has by default.
+type Binding struct {
+	Name     string
+	Defaults []input.Key
+}
+
+// Bindings is every action that can be rebound, in the order they are listed in the settings.
+var Bindings = []Binding{
+	{"Left", []input.Key{input.KeyGamepadLStickLeft, input.KeyLeft, input.KeyA}},
+	{"Right", []input.Key{input.KeyGamepadLStickRight, input.KeyRight, input.KeyD}},
+	{"Up", []input.Key{input.KeyGamepadLStickUp, input.KeyUp, input.KeyW}},
+	{"Down", []input.Key{input.KeyGamepadLStickDown, input.KeyDown, input.KeyS}},
+	{"Confirm", []input.Key{input.KeyMouseLeft, input.KeyEnter, input.KeyGamepadA, input.KeyTouchTap}},
+	{"Cancel", []input.Key{input.KeyMouseRight, input.KeyGamepadB}},
+	{"Look", []input.Key{input.KeyGamepadX, input.KeyTouchLongTap}},
+	{"Pointer", []input.Key{input.KeyGamepadRStickMotion}},
+	{"Drag", []input.Key{input.KeyTouchDrag}},
+	{"Pause", []input.Key{input.KeyEscape, input.KeyGamepadStart}},
+	{"Inventory", []input.Key{input.KeyI, input.KeyTab, input.KeyGamepadY}},
+	{"PrevItem", []input.Key{input.KeyQ, input.KeyGamepadL1}},
+	{"NextItem", []input.Key{input.KeyE, input.KeyGamepadR1}},
+	{"ZoomIn", []input.Key{input.KeyWheelUp, input.KeyEqual}},
+	{"ZoomOut", []input.Key{input.KeyWheelDown, input.KeyMinus}},
+	{"Mute", []input.Key{input.KeyM}},
+}
+
+// renamedBindings maps the old names of bindings to their current ones, so configs saved before a rename keep their keys.
+var renamedBindings = map[string]string{
+	"Move": "Confirm",
+}
+
+// renameBindings moves any bindings saved under old names to their current ones, unless the current one has already been set.
+func renameBindings(bindings map[string][]string) {
+	for old, name := range renamedBindings {
+		if keys, ok := bindings[old]; ok {
+			if _, ok := bindings[name]; !ok {
+				bindings[name] = keys
+			}
+			delete(bindings, old)
+		}
+	}
+}
+
+// binding returns the named binding.
+func binding(name string) (Binding, bool) {
+	for _, b := range Bindings {
+		if b.Name == name {
+			return b, true
+		}
+	}
+	return Binding{}, false
+}
+
+// Keys returns the keys bound to the named binding.
+func Keys(name string) []input.Key {
+	names, ok := Current.Bindings[name]
+	if !ok {
+		b, _ := binding(name)
+		return b.Defaults
+	}
+	var keys []input.Key
+	for _, n := range names {
+		k, err := input.ParseKey(n)
+		if err != nil {
+			fmt.Println("bad binding for", name, err)
+			continue
+		}
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// SetKeys rebinds the named binding.
+func SetKeys(name string, keys []input.Key) {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k.String()
+	}
+	Current.Bindings[name] = names
+}
+
+// ResetKeys puts every binding back to its defaults.
+func ResetKeys() {
+	clear(Current.Bindings)
+}
+
+// Keymap makes a keymap from actions to the names of the bindings they use.
+func Keymap(actions map[input.Action]string) input.Keymap {
+	keymap := make(input.Keymap, len(actions))
+	for action, name := range actions {
+		keymap[action] = Keys(name)
+	}
+	return keymap
+}
@@ -0,0 +1,104 @@
+// Package config holds the player's settings, kept between runs in the user's config directory, or local storage in browsers. Other files kept between runs, like saved games, go there too.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/ehh24/pkg/audio"
+	"github.com/kettek/ehh24/pkg/lang"
+)
+
+// Config is every setting.
+type Config struct {
+	Volumes    map[string]float64 // Volume of each audio bus, by name.
+	Muted      bool
+	Fullscreen bool
+	Language   lang.Language
+	TextSpeed  float64             // Characters a tick that speech is typed out at. Zero shows it all at once.
+	Bindings   map[string][]string // Keys bound to actions that have been rebound, by binding name. Keys are named as ebitengine-input names them.
+}
+
+// Current is the config in use.
+var Current = Default()
+
+// Default returns the default config.
+func Default() Config {
+	c := Config{
+		Volumes:   make(map[string]float64),
+		TextSpeed: 0.5,
+		Bindings:  make(map[string][]string),
+	}
+	for _, bus := range audio.Buses {
+		c.Volumes[bus.String()] = audio.Volume(bus)
+	}
+	return c
+}
+
+// Load reads the config, keeping the defaults for anything it doesn't have. Not having a config at all is fine.
+func Load() error {
+	data, err := read("config")
+	if err != nil || data == nil {
+		return err
+	}
+	c := Default()
+	if err := json.Unmarshal(data, &c); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	if c.Volumes == nil {
+		c.Volumes = make(map[string]float64)
+	}
+	if c.Bindings == nil {
+		c.Bindings = make(map[string][]string)
+	}
+	renameBindings(c.Bindings)
+	Current = c
+	return nil
+}
+
+// Save writes the current config.
+func Save() error {
+	data, err := json.MarshalIndent(Current, "", "\t")
+	if err != nil {
+		return err
+	}
+	return write("config", data)
+}
+
+// ReadFile reads a file kept alongside the config, returning nil if there isn't one.
+func ReadFile(name string) ([]byte, error) {
+	return read(name)
+}
+
+// WriteFile writes a file kept alongside the config.
+func WriteFile(name string, data []byte) error {
+	return write(name, data)
+}
+
+// RemoveFile removes a file kept alongside the config.
+func RemoveFile(name string) error {
+	return remove(name)
+}
+
+// Apply puts the current config into effect.
+func Apply() {
+	for _, bus := range audio.Buses {
+		if v, ok := Current.Volumes[bus.String()]; ok {
+			audio.SetVolume(bus, v)
+		}
+	}
+	audio.SetMuted(Current.Muted)
+	ebiten.SetFullscreen(Current.Fullscreen)
+	lang.Current = Current.Language
+}
+
+// Capture takes the settings that can be changed elsewhere, such as the volume, into the current config.
+func Capture() {
+	for _, bus := range audio.Buses {
+		Current.Volumes[bus.String()] = audio.Volume(bus)
+	}
+	Current.Muted = audio.Muted()
+	Current.Fullscreen = ebiten.IsFullscreen()
+	Current.Language = lang.Current
+}
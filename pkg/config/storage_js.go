@@ -0,0 +1,41 @@
+package config
+
+import "syscall/js"
+
+// storageKey returns where the named file is kept in local storage.
+func storageKey(name string) string {
+	return "ehh24-" + name
+}
+
+// read reads the named file from local storage, returning nil if there isn't one yet.
+func read(name string) ([]byte, error) {
+	storage := js.Global().Get("localStorage")
+	if !storage.Truthy() {
+		return nil, nil
+	}
+	v := storage.Call("getItem", storageKey(name))
+	if v.IsNull() {
+		return nil, nil
+	}
+	return []byte(v.String()), nil
+}
+
+// write writes the named file to local storage.
+func write(name string, data []byte) error {
+	storage := js.Global().Get("localStorage")
+	if !storage.Truthy() {
+		return nil
+	}
+	storage.Call("setItem", storageKey(name), string(data))
+	return nil
+}
+
+// remove removes the named file from local storage.
+func remove(name string) error {
+	storage := js.Global().Get("localStorage")
+	if !storage.Truthy() {
+		return nil
+	}
+	storage.Call("removeItem", storageKey(name))
+	return nil
+}
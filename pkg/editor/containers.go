@@ -0,0 +1,77 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/ebitengine/debugui"
+	"github.com/kettek/ehh24/pkg/res"
+)
+
+func (s *State) windowContainers(ctx *debugui.Context) {
+	ctx.Window("Containers", posContainers.Rect(), func(resp debugui.Response, layout debugui.Layout) {
+		s.windowAreas["Containers"] = layout.Rect
+
+		ctx.SetLayoutRow([]int{-1}, 0)
+		if ctx.Button("Add Container") != 0 {
+			s.place.Containers = append(s.place.Containers, &res.Container{
+				Tag: fmt.Sprintf("container%d", len(s.place.Containers)),
+			})
+			s.selectedContainerIndex = len(s.place.Containers) - 1
+		}
+		for i, container := range s.place.Containers {
+			label := fmt.Sprintf("%d %s %s", i, container.Kind, container.Tag)
+			if i == s.selectedContainerIndex {
+				ctx.Label("> " + label)
+			} else if ctx.Button(label) != 0 {
+				s.selectedContainerIndex = i
+			}
+		}
+
+		if s.selectedContainerIndex < 0 || s.selectedContainerIndex >= len(s.place.Containers) {
+			return
+		}
+		container := s.place.Containers[s.selectedContainerIndex]
+
+		ctx.SetLayoutRow([]int{labelWidth, -1}, 0)
+		ctx.Label("Tag")
+		if ctx.TextBox(&container.Tag)&debugui.ResponseSubmit != 0 {
+			ctx.SetFocus()
+		}
+		ctx.Label("Kind")
+		if ctx.Button(container.Kind.String()) != 0 {
+			container.Kind = res.ContainerKinds[(int(container.Kind)+1)%len(res.ContainerKinds)]
+		}
+		ctx.Label("Items")
+		if ctx.TextBox(&container.Items)&debugui.ResponseSubmit != 0 {
+			ctx.SetFocus()
+		}
+		ctx.Label("Accepts")
+		if ctx.TextBox(&container.Accepts)&debugui.ResponseSubmit != 0 {
+			ctx.SetFocus()
+		}
+		s.containerCapacity = float64(container.Capacity)
+		ctx.Label("Slots")
+		if ctx.Number(&s.containerCapacity, 1, 0) != 0 {
+			container.Capacity = max(0, int(s.containerCapacity))
+		}
+		// Done when TargetItem is put in.
+		ctx.Label("Item")
+		if ctx.TextBox(&container.TargetItem)&debugui.ResponseSubmit != 0 {
+			ctx.SetFocus()
+		}
+		ctx.Label("Target")
+		if ctx.TextBox(&container.TargetTag)&debugui.ResponseSubmit != 0 {
+			ctx.SetFocus()
+		}
+		ctx.Label("Action")
+		if ctx.TextBox(&container.TargetAction)&debugui.ResponseSubmit != 0 {
+			ctx.SetFocus()
+		}
+
+		ctx.SetLayoutRow([]int{-1}, 0)
+		if ctx.Button("Delete Container") != 0 {
+			s.place.Containers = append(s.place.Containers[:s.selectedContainerIndex], s.place.Containers[s.selectedContainerIndex+1:]...)
+			s.selectedContainerIndex = -1
+		}
+	})
+}
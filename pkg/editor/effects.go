@@ -0,0 +1,78 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/ebitengine/debugui"
+	"github.com/kettek/ehh24/pkg/post"
+	"github.com/kettek/ehh24/pkg/res"
+)
+
+// maxEffectParams is as many params as an effect kind has.
+const maxEffectParams = 8
+
+func (s *State) windowEffects(ctx *debugui.Context) {
+	ctx.Window("Effects", posEffects.Rect(), func(resp debugui.Response, layout debugui.Layout) {
+		s.windowAreas["Effects"] = layout.Rect
+
+		ctx.SetLayoutRow([]int{-1}, 0)
+		if ctx.Button("Add Effect") != 0 {
+			s.place.Effects = append(s.place.Effects, &res.Effect{
+				Kind:   res.EffectKindVignette,
+				Params: make(map[string]float64),
+			})
+			s.selectedEffectIndex = len(s.place.Effects) - 1
+		}
+		for i, effect := range s.place.Effects {
+			label := fmt.Sprintf("%d %s", i, effect.Kind)
+			if i == s.selectedEffectIndex {
+				ctx.Label("> " + label)
+			} else if ctx.Button(label) != 0 {
+				s.selectedEffectIndex = i
+			}
+		}
+
+		if s.selectedEffectIndex < 0 || s.selectedEffectIndex >= len(s.place.Effects) {
+			return
+		}
+		effect := s.place.Effects[s.selectedEffectIndex]
+		if effect.Params == nil {
+			effect.Params = make(map[string]float64)
+		}
+
+		ctx.SetLayoutRow([]int{labelWidth, -1}, 0)
+		ctx.Label("Kind")
+		if ctx.Button(effect.Kind.String()) != 0 {
+			effect.Kind = res.EffectKinds[(int(effect.Kind)+1)%len(res.EffectKinds)]
+			clear(effect.Params)
+		}
+		if effect.Kind == res.EffectKindGrade {
+			ctx.Label("LUT")
+			if ctx.TextBox(&effect.LUT)&debugui.ResponseSubmit != 0 {
+				ctx.SetFocus()
+			}
+		}
+
+		// Params are shown with their defaults until changed.
+		for i, name := range post.Params(effect.Kind) {
+			if i >= maxEffectParams {
+				break
+			}
+			v, ok := effect.Params[name]
+			if !ok {
+				v = post.Default(effect.Kind, name)
+			}
+			s.effectParams[i] = v
+			ctx.Label(name)
+			if ctx.Number(&s.effectParams[i], 0.05, 2) != 0 {
+				effect.Params[name] = s.effectParams[i]
+			}
+		}
+
+		ctx.SetLayoutRow([]int{-1}, 0)
+		if ctx.Button("Delete Effect") != 0 {
+			s.place.Effects = append(s.place.Effects[:s.selectedEffectIndex], s.place.Effects[s.selectedEffectIndex+1:]...)
+			s.selectedEffectIndex = -1
+		}
+	})
+}
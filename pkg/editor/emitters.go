@@ -0,0 +1,145 @@
+package editor
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/ebitengine/debugui"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/kettek/ehh24/pkg/res"
+)
+
+// emitterEdit holds the emitter fields that debugui can't edit in place, since they aren't float64s.
+type emitterEdit struct {
+	X, Y                             float64
+	Start, End                       colorEdit
+	Burst, Max, Lifetime, LifeSpread float64
+}
+
+func (s *State) windowEmitters(ctx *debugui.Context) {
+	ctx.Window("Emitters", posEmitters.Rect(), func(resp debugui.Response, layout debugui.Layout) {
+		s.windowAreas["Emitters"] = layout.Rect
+
+		ctx.SetLayoutRow([]int{-1}, 0)
+		if ctx.Button("Add Emitter") != 0 {
+			emitter := res.MakeEmitter()
+			emitter.Point = image.Pt(int(s.scrollX+100), int(s.scrollY+100))
+			s.place.Emitters = append(s.place.Emitters, emitter)
+			s.selectedEmitterIndex = len(s.place.Emitters) - 1
+		}
+		for i, emitter := range s.place.Emitters {
+			label := fmt.Sprintf("%d %s %s", i, emitter.Sprite, emitter.Tag)
+			if i == s.selectedEmitterIndex {
+				ctx.Label("> " + label)
+			} else if ctx.Button(label) != 0 {
+				s.selectedEmitterIndex = i
+			}
+		}
+
+		if s.selectedEmitterIndex < 0 || s.selectedEmitterIndex >= len(s.place.Emitters) {
+			return
+		}
+		emitter := s.place.Emitters[s.selectedEmitterIndex]
+
+		ctx.SetLayoutRow([]int{labelWidth, -1}, 0)
+		ctx.Label("Tag")
+		if ctx.TextBox(&emitter.Tag)&debugui.ResponseSubmit != 0 {
+			ctx.SetFocus()
+		}
+		ctx.Label("Sprite")
+		if ctx.TextBox(&emitter.Sprite)&debugui.ResponseSubmit != 0 {
+			ctx.SetFocus()
+		}
+		ctx.Label("Layer")
+		if ctx.Button(emitter.Layer.String()) != 0 {
+			emitter.Layer = res.StaticLayers[(int(emitter.Layer)+1)%len(res.StaticLayers)]
+		}
+		ctx.Label("Enabled")
+		enabled := !emitter.Disabled
+		if ctx.Checkbox("", &enabled) != 0 {
+			emitter.Disabled = !enabled
+		}
+
+		s.emitterEdit = emitterEdit{
+			X:          float64(emitter.Point.X),
+			Y:          float64(emitter.Point.Y),
+			Start:      newColorEdit(emitter.StartColor),
+			End:        newColorEdit(emitter.EndColor),
+			Burst:      float64(emitter.Burst),
+			Max:        float64(emitter.Max),
+			Lifetime:   float64(emitter.Lifetime),
+			LifeSpread: float64(emitter.LifetimeSpread),
+		}
+		ctx.SetLayoutRow([]int{labelWidth, 60, 60}, 0)
+		ctx.Label("Point")
+		if ctx.Number(&s.emitterEdit.X, 1, 0) != 0 || ctx.Number(&s.emitterEdit.Y, 1, 0) != 0 {
+			emitter.Point = image.Pt(int(s.emitterEdit.X), int(s.emitterEdit.Y))
+		}
+		ctx.Label("Burst/Max")
+		if ctx.Number(&s.emitterEdit.Burst, 1, 0) != 0 || ctx.Number(&s.emitterEdit.Max, 1, 0) != 0 {
+			emitter.Burst = max(0, int(s.emitterEdit.Burst))
+			emitter.Max = max(1, int(s.emitterEdit.Max))
+		}
+		ctx.Label("Life")
+		if ctx.Number(&s.emitterEdit.Lifetime, 1, 0) != 0 || ctx.Number(&s.emitterEdit.LifeSpread, 1, 0) != 0 {
+			emitter.Lifetime = max(1, int(s.emitterEdit.Lifetime))
+			emitter.LifetimeSpread = max(0, int(s.emitterEdit.LifeSpread))
+		}
+
+		ctx.SetLayoutRow([]int{labelWidth, 35, 35, 35, 35}, 0)
+		ctx.Label("Start")
+		if colorNumbers(ctx, &s.emitterEdit.Start) {
+			emitter.StartColor = s.emitterEdit.Start.color()
+		}
+		ctx.Label("End")
+		if colorNumbers(ctx, &s.emitterEdit.End) {
+			emitter.EndColor = s.emitterEdit.End.color()
+		}
+
+		ctx.SetLayoutRow([]int{labelWidth, -1}, 0)
+		ctx.Label("Rate")
+		ctx.Slider(&emitter.Rate, 0, 10, 0.05, 2)
+		ctx.Label("Angle")
+		ctx.Slider(&emitter.Angle, -math.Pi, math.Pi, 0.05, 2)
+		ctx.Label("Spread")
+		ctx.Slider(&emitter.Spread, 0, math.Pi*2, 0.05, 2)
+		ctx.Label("Speed")
+		ctx.Slider(&emitter.Speed, 0, 10, 0.05, 2)
+		ctx.Label("+Speed")
+		ctx.Slider(&emitter.SpeedSpread, 0, 10, 0.05, 2)
+		ctx.Label("Gravity")
+		ctx.Slider(&emitter.Gravity, -1, 1, 0.01, 2)
+		ctx.Label("Drag")
+		ctx.Slider(&emitter.Drag, 0, 1, 0.01, 2)
+		ctx.Label("Scale")
+		ctx.Slider(&emitter.StartScale, 0, 8, 0.05, 2)
+		ctx.Label("Scale>")
+		ctx.Slider(&emitter.EndScale, 0, 8, 0.05, 2)
+
+		ctx.SetLayoutRow([]int{-1}, 0)
+		if ctx.Button("Delete Emitter") != 0 {
+			s.place.Emitters = append(s.place.Emitters[:s.selectedEmitterIndex], s.place.Emitters[s.selectedEmitterIndex+1:]...)
+			s.selectedEmitterIndex = -1
+		}
+	})
+}
+
+// drawEmitters marks where the place's emitters are and which way they spray.
+func (s *State) drawEmitters(screen *ebiten.Image, op *ebiten.DrawImageOptions) {
+	for i, emitter := range s.place.Emitters {
+		x, y := op.GeoM.Apply(float64(emitter.Point.X), float64(emitter.Point.Y))
+		r := 8 * op.GeoM.Element(0, 0)
+		clr := emitter.StartColor
+		clr.A = 0x80
+		width := float32(1)
+		if i == s.selectedEmitterIndex {
+			width = 2
+		}
+		vector.StrokeRect(screen, float32(x)-3, float32(y)-3, 6, 6, width, clr, true)
+		for _, a := range []float64{emitter.Angle - emitter.Spread/2, emitter.Angle + emitter.Spread/2} {
+			vector.StrokeLine(screen, float32(x), float32(y), float32(x+math.Cos(a)*r), float32(y+math.Sin(a)*r), width, clr, true)
+		}
+	}
+}
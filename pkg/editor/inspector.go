@@ -0,0 +1,139 @@
+package editor
+
+import (
+	"image"
+	"math"
+
+	"github.com/ebitengine/debugui"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/ehh24/pkg/res"
+)
+
+const thumbnailSize = 32
+
+// inspectStatic shows editable properties for the given static.
+func (s *State) inspectStatic(ctx *debugui.Context, static *res.Static) {
+	ctx.SetLayoutRow([]int{labelWidth, -1}, 0)
+	ctx.Label("Name")
+	if ctx.TextBox(&static.Name)&debugui.ResponseSubmit != 0 {
+		ctx.SetFocus()
+	}
+
+	ctx.SetLayoutRow([]int{labelWidth, 60, 60}, 0)
+	ctx.Label("Point")
+	// Numbers are identified by pointer, so we go through our own fields to keep them stable between frames.
+	s.inspectX = float64(static.Point.X)
+	s.inspectY = float64(static.Point.Y)
+	if ctx.Number(&s.inspectX, 1, 0) != 0 {
+		static.Point.X = int(s.inspectX)
+	}
+	if ctx.Number(&s.inspectY, 1, 0) != 0 {
+		static.Point.Y = int(s.inspectY)
+	}
+
+	ctx.SetLayoutRow([]int{labelWidth, -1}, 0)
+	st, err := res.GetStax(static.Name)
+	if err == nil {
+		ctx.Popup("Change Stack", func(resp debugui.Response, layout debugui.Layout) {
+			s.windowAreas["Popup"] = layout.Rect
+			for _, stack := range st.Stax.Stacks {
+				if ctx.Button(stack.Name) != 0 {
+					static.Stack = stack.Name
+					static.Animation = ""
+				}
+			}
+		})
+		ctx.Label("Stack")
+		if ctx.Button(orFirst(static.Stack)) != 0 {
+			ctx.OpenPopup("Change Stack")
+		}
+
+		ctx.Popup("Change Animation", func(resp debugui.Response, layout debugui.Layout) {
+			s.windowAreas["Popup"] = layout.Rect
+			stack := st.Stax.Stack(static.Stack)
+			if stack == nil && len(st.Stax.Stacks) > 0 {
+				stack = &st.Stax.Stacks[0]
+			}
+			if stack == nil {
+				return
+			}
+			for _, anim := range stack.Animations {
+				if ctx.Button(anim.Name) != 0 {
+					static.Animation = anim.Name
+				}
+			}
+		})
+		ctx.Label("Anim")
+		if ctx.Button(orFirst(static.Animation)) != 0 {
+			ctx.OpenPopup("Change Animation")
+		}
+		ctx.Label("Source")
+		ctx.Label(res.SourceOf(static.Name + ".png"))
+	} else {
+		ctx.Label("Stack")
+		ctx.Label("missing stax")
+	}
+
+	ctx.Label("Tag")
+	if ctx.TextBox(&static.Tag)&debugui.ResponseSubmit != 0 {
+		ctx.SetFocus()
+	}
+
+	ctx.Popup("Change Layer", func(resp debugui.Response, layout debugui.Layout) {
+		s.windowAreas["Popup"] = layout.Rect
+		for _, layer := range res.StaticLayers {
+			if ctx.Button(layer.String()) != 0 {
+				static.Layer = layer
+			}
+		}
+	})
+	ctx.Label("Layer")
+	if ctx.Button(static.Layer.String()) != 0 {
+		ctx.OpenPopup("Change Layer")
+	}
+	ctx.SetLayoutRow([]int{-1}, 0)
+}
+
+// staxThumbnail draws a thumbnail of the given stax as a control.
+func (s *State) staxThumbnail(ctx *debugui.Context, name string, st res.StaxImage) {
+	ctx.Control(name, func(r image.Rectangle) debugui.Response {
+		ctx.DrawControl(func(screen *ebiten.Image) {
+			static := res.Static{Name: name}
+			frame := static.Frame(st)
+			if frame == nil {
+				return
+			}
+			// Fit the full height of the stacked slices within the thumbnail.
+			w := float64(st.Stax.SliceWidth)
+			h := float64(st.Stax.SliceHeight) + 1.5*float64(len(frame.Slices))
+			scale := math.Max(1, math.Floor(math.Min(float64(r.Dx())/w, float64(r.Dy())/h)))
+
+			static.Point = image.Pt(int(w/2), int(h))
+
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Scale(scale, scale)
+			op.GeoM.Translate(float64(r.Min.X)+(float64(r.Dx())-w*scale)/2, float64(r.Min.Y)+(float64(r.Dy())-h*scale)/2)
+			static.Draw(screen.SubImage(r).(*ebiten.Image), op)
+		})
+		return 0
+	})
+}
+
+// staxList lists all staxii with thumbnails, calling pick when one is chosen.
+func (s *State) staxList(ctx *debugui.Context, pick func(name string)) {
+	ctx.SetLayoutRow([]int{thumbnailSize, -1}, thumbnailSize)
+	for _, stax := range s.sortedStaxii(res.Staxii) {
+		s.staxThumbnail(ctx, stax.Name, stax.StaxImage)
+		if ctx.Button(stax.Name) != 0 {
+			pick(stax.Name)
+		}
+	}
+	ctx.SetLayoutRow([]int{-1}, 0)
+}
+
+func orFirst(s string) string {
+	if s == "" {
+		return "(first)"
+	}
+	return s
+}
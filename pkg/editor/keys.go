@@ -0,0 +1,207 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/kettek/ehh24/pkg/res"
+)
+
+// Binding is an editor action that can be bound to keys.
+type Binding string
+
+// Our editor bindings.
+const (
+	BindToolNone          Binding = "ToolNone"
+	BindToolStatic        Binding = "ToolStatic"
+	BindToolPolygon       Binding = "ToolPolygon"
+	BindToolPolygonSelect Binding = "ToolPolygonSelect"
+	BindToolFloor         Binding = "ToolFloor"
+	BindGrid              Binding = "Grid"
+	BindZoomIn            Binding = "ZoomIn"
+	BindZoomOut           Binding = "ZoomOut"
+	BindPanLeft           Binding = "PanLeft"
+	BindPanRight          Binding = "PanRight"
+	BindPanUp             Binding = "PanUp"
+	BindPanDown           Binding = "PanDown"
+	BindSave              Binding = "Save"
+	BindOpen              Binding = "Open"
+)
+
+// BindingsFile is the res file that editor key bindings are read from.
+const BindingsFile = "editor.cfg"
+
+// KeyCombo is a key with an optional set of modifiers, such as "Control+S".
+type KeyCombo struct {
+	Key       ebiten.Key
+	Modifiers []ebiten.Key
+}
+
+// UnmarshalText parses a "+" separated key combo, with the last part being the key.
+func (k *KeyCombo) UnmarshalText(text []byte) error {
+	parts := strings.Split(string(text), "+")
+	k.Modifiers = nil
+	for i, part := range parts {
+		var key ebiten.Key
+		if err := key.UnmarshalText([]byte(strings.TrimSpace(part))); err != nil {
+			return err
+		}
+		if i == len(parts)-1 {
+			k.Key = key
+		} else {
+			k.Modifiers = append(k.Modifiers, key)
+		}
+	}
+	return nil
+}
+
+// MarshalText writes the combo back out in the same "+" separated form.
+func (k KeyCombo) MarshalText() ([]byte, error) {
+	var parts []string
+	for _, m := range k.Modifiers {
+		parts = append(parts, m.String())
+	}
+	parts = append(parts, k.Key.String())
+	return []byte(strings.Join(parts, "+")), nil
+}
+
+// modifiersHeld returns if exactly the combo's modifiers are held, so that "S" does not fire for "Control+S".
+func (k KeyCombo) modifiersHeld() bool {
+	for _, m := range []ebiten.Key{ebiten.KeyControl, ebiten.KeyShift, ebiten.KeyAlt, ebiten.KeyMeta} {
+		want := false
+		for _, m2 := range k.Modifiers {
+			if m == m2 {
+				want = true
+				break
+			}
+		}
+		if ebiten.IsKeyPressed(m) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// JustPressed returns if the combo was just pressed.
+func (k KeyCombo) JustPressed() bool {
+	return inpututil.IsKeyJustPressed(k.Key) && k.modifiersHeld()
+}
+
+// Pressed returns if the combo is being held.
+func (k KeyCombo) Pressed() bool {
+	return ebiten.IsKeyPressed(k.Key) && k.modifiersHeld()
+}
+
+// Bindings maps editor actions to key combos.
+type Bindings map[Binding][]KeyCombo
+
+// JustPressed returns if any of the action's combos were just pressed.
+func (b Bindings) JustPressed(action Binding) bool {
+	for _, k := range b[action] {
+		if k.JustPressed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Pressed returns if any of the action's combos are being held.
+func (b Bindings) Pressed(action Binding) bool {
+	for _, k := range b[action] {
+		if k.Pressed() {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadBindings reads the editor bindings from res.
+func ReadBindings() (Bindings, error) {
+	data, err := res.ReadFile(BindingsFile)
+	if err != nil {
+		return nil, err
+	}
+	var b Bindings
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("%s: %w", BindingsFile, err)
+	}
+	return b, nil
+}
+
+// updateShortcuts handles keyboard shortcuts and the mouse wheel. Keys are left alone while a popup is open or the UI may be taking them.
+func (s *State) updateShortcuts() {
+	if _, wy := ebiten.Wheel(); wy > 0 {
+		s.zoom(1)
+	} else if wy < 0 {
+		s.zoom(-1)
+	}
+	if _, ok := s.windowAreas["Popup"]; ok || s.uiFocused {
+		return
+	}
+
+	switch {
+	case s.bindings.JustPressed(BindToolNone):
+		s.tool = &ToolNone{}
+	case s.bindings.JustPressed(BindToolStatic):
+		s.tool = &ToolStatic{}
+	case s.bindings.JustPressed(BindToolPolygon):
+		s.tool = &ToolPolygon{}
+	case s.bindings.JustPressed(BindToolPolygonSelect):
+		s.tool = &ToolPolygonSelect{}
+	case s.bindings.JustPressed(BindToolFloor):
+		s.tool = &ToolFloor{}
+	}
+
+	if s.bindings.JustPressed(BindGrid) {
+		grid := s.layer(layerGrid)
+		grid.Visible = !grid.Visible
+	}
+
+	if s.bindings.JustPressed(BindZoomIn) {
+		s.zoom(1)
+	} else if s.bindings.JustPressed(BindZoomOut) {
+		s.zoom(-1)
+	}
+
+	const panSpeed = 4
+	if s.bindings.Pressed(BindPanLeft) {
+		s.scrollX -= panSpeed
+	} else if s.bindings.Pressed(BindPanRight) {
+		s.scrollX += panSpeed
+	}
+	if s.bindings.Pressed(BindPanUp) {
+		s.scrollY -= panSpeed
+	} else if s.bindings.Pressed(BindPanDown) {
+		s.scrollY += panSpeed
+	}
+
+	if s.bindings.JustPressed(BindSave) {
+		if s.pendingFilename != "" {
+			s.save()
+		} else {
+			s.pendingPopup = "Save"
+		}
+	} else if s.bindings.JustPressed(BindOpen) {
+		s.pendingPopup = "Open"
+	}
+}
+
+// zoom zooms in or out by the given steps while keeping the point under the cursor in place.
+func (s *State) zoom(steps float64) {
+	x, y := ebiten.CursorPosition()
+	wx := float64(x)/s.scale + s.scrollX
+	wy := float64(y)/s.scale + s.scrollY
+
+	s.scale += steps
+	if s.scale < 1 {
+		s.scale = 1
+	} else if s.scale > 8 {
+		s.scale = 8
+	}
+
+	s.scrollX = wx - float64(x)/s.scale
+	s.scrollY = wy - float64(y)/s.scale
+}
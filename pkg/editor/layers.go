@@ -0,0 +1,158 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/ebitengine/debugui"
+	"github.com/kettek/ehh24/pkg/res"
+)
+
+// Names of our built-in editor layers.
+const (
+	layerFloors   = "Floors"
+	layerGrid     = "Grid"
+	layerStatics  = "Statics"
+	layerPolygons = "Polygons"
+)
+
+// polygonKinds are the kinds that get their own polygon sub-layer.
+var polygonKinds = []res.PolygonKind{res.PolygonKindNone, res.PolygonKindBlock, res.PolygonKindTrigger, res.PolygonKindInteract}
+
+// layerView is the editor-only view state of a layer. It is not saved to the place.
+type layerView struct {
+	Visible bool
+	Locked  bool
+	Opacity float64
+}
+
+// layer returns the view state of the named layer, creating it if needed.
+func (s *State) layer(name string) *layerView {
+	l, ok := s.layers[name]
+	if !ok {
+		l = &layerView{Visible: true, Opacity: 1}
+		s.layers[name] = l
+	}
+	return l
+}
+
+// polygonLayerName returns the name of the polygon sub-layer for a kind.
+func polygonLayerName(kind res.PolygonKind) string {
+	return layerPolygons + "/" + kind.String()
+}
+
+// placeLayerName returns the name used for a place's decorative layer.
+func placeLayerName(layer *res.Layer) string {
+	return "Layer/" + layer.Name
+}
+
+// polygonVisible returns if the polygon's layer and kind sub-layer are shown.
+func (s *State) polygonVisible(p *res.Polygon) bool {
+	return s.layer(layerPolygons).Visible && s.layer(polygonLayerName(p.Kind)).Visible
+}
+
+// polygonLocked returns if the polygon's layer or kind sub-layer is locked.
+func (s *State) polygonLocked(p *res.Polygon) bool {
+	return s.layer(layerPolygons).Locked || s.layer(polygonLayerName(p.Kind)).Locked
+}
+
+// polygonOpacity returns the combined opacity of the polygon's layer and kind sub-layer.
+func (s *State) polygonOpacity(p *res.Polygon) float64 {
+	return s.layer(layerPolygons).Opacity * s.layer(polygonLayerName(p.Kind)).Opacity
+}
+
+// currentStaticsLayer returns the name of the layer the static tool currently targets.
+func (s *State) currentStaticsLayer() string {
+	if s.activeLayer >= 0 && s.activeLayer < len(s.place.Layers) {
+		return placeLayerName(s.place.Layers[s.activeLayer])
+	}
+	return layerStatics
+}
+
+// currentStatics returns the statics the static tool currently targets, being either the place's statics or a decorative layer's.
+func (s *State) currentStatics() *[]*res.Static {
+	if s.activeLayer >= 0 && s.activeLayer < len(s.place.Layers) {
+		return &s.place.Layers[s.activeLayer].Statics
+	}
+	return &s.place.Statics
+}
+
+// toolLocked returns if the current tool's layer is locked or hidden.
+func (s *State) toolLocked() bool {
+	var l *layerView
+	switch s.tool.(type) {
+	case *ToolStatic:
+		l = s.layer(s.currentStaticsLayer())
+	case *ToolFloor:
+		l = s.layer(layerFloors)
+	case *ToolPolygon, *ToolPolygonSelect:
+		l = s.layer(layerPolygons)
+	default:
+		return false
+	}
+	return l.Locked || !l.Visible
+}
+
+func (s *State) windowLayers(ctx *debugui.Context) {
+	ctx.Window("Layers", posLayers.Rect(), func(resp debugui.Response, layout debugui.Layout) {
+		s.windowAreas["Layers"] = layout.Rect
+
+		ctx.SetLayoutRow([]int{60, 20, 20, -1}, 0)
+		ctx.Label("")
+		ctx.Label("Vis")
+		ctx.Label("Lck")
+		ctx.Label("Opacity")
+		s.layerRow(ctx, layerFloors, layerFloors)
+		s.layerRow(ctx, layerGrid, layerGrid)
+		s.layerRow(ctx, layerStatics, layerStatics)
+		s.layerRow(ctx, layerPolygons, layerPolygons)
+		for _, kind := range polygonKinds {
+			s.layerRow(ctx, polygonLayerName(kind), " "+kind.String())
+		}
+
+		if ctx.Header("Place Layers", true) != 0 {
+			ctx.SetLayoutRow([]int{-1}, 0)
+			if ctx.Button("Add Layer") != 0 {
+				s.place.Layers = append(s.place.Layers, res.MakeLayer(fmt.Sprintf("Layer %d", len(s.place.Layers)+1)))
+			}
+			if s.activeLayer == -1 {
+				ctx.Label("Stax tool: Statics")
+			} else if ctx.Button("Stax tool: Statics") != 0 {
+				s.activeLayer = -1
+			}
+			for i, layer := range s.place.Layers {
+				s.layerRow(ctx, placeLayerName(layer), layer.Name)
+				ctx.SetLayoutRow([]int{labelWidth, -1}, 0)
+				ctx.Label("Name")
+				if ctx.TextBox(&layer.Name)&debugui.ResponseSubmit != 0 {
+					ctx.SetFocus()
+				}
+				ctx.Label("Opacity")
+				ctx.Slider(&layer.Opacity, 0, 1, 0.05, 2)
+				ctx.SetLayoutRow([]int{70, 60, -1}, 0)
+				if s.activeLayer == i {
+					ctx.Label("Editing")
+				} else if ctx.Button(fmt.Sprintf("Edit\x00%d", i)) != 0 {
+					s.activeLayer = i
+				}
+				if ctx.Button(fmt.Sprintf("Delete\x00%d", i)) != 0 {
+					s.place.Layers = append(s.place.Layers[:i], s.place.Layers[i+1:]...)
+					s.activeLayer = -1
+					break
+				}
+				ctx.Label("")
+			}
+			ctx.SetLayoutRow([]int{-1}, 0)
+		}
+	})
+}
+
+// layerRow shows the visibility and lock toggles and the opacity slider for a layer.
+func (s *State) layerRow(ctx *debugui.Context, name, label string) {
+	l := s.layer(name)
+	ctx.SetLayoutRow([]int{60, 20, 20, -1}, 0)
+	ctx.Label(label)
+	ctx.Checkbox("", &l.Visible)
+	ctx.Checkbox("", &l.Locked)
+	ctx.Slider(&l.Opacity, 0, 1, 0.05, 2)
+	ctx.SetLayoutRow([]int{-1}, 0)
+}
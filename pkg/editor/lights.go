@@ -0,0 +1,144 @@
+package editor
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/ebitengine/debugui"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/kettek/ehh24/pkg/res"
+)
+
+// lightEdit holds the light fields that debugui can't edit in place, since they aren't float64s.
+type lightEdit struct {
+	X, Y  float64
+	Color colorEdit
+}
+
+// colorEdit holds a color's channels as float64s, so debugui can edit them.
+type colorEdit struct {
+	R, G, B, A float64
+}
+
+func (s *State) windowLights(ctx *debugui.Context) {
+	ctx.Window("Lights", posLights.Rect(), func(resp debugui.Response, layout debugui.Layout) {
+		s.windowAreas["Lights"] = layout.Rect
+
+		ctx.SetLayoutRow([]int{labelWidth, 35, 35, 35, 35}, 0)
+		ctx.Label("Ambient")
+		s.ambientEdit = newColorEdit(s.place.Ambient)
+		if colorNumbers(ctx, &s.ambientEdit) {
+			s.place.Ambient = s.ambientEdit.color()
+		}
+
+		ctx.SetLayoutRow([]int{-1}, 0)
+		if ctx.Button("Add Light") != 0 {
+			light := res.MakeLight()
+			light.Point = image.Pt(int(s.scrollX+100), int(s.scrollY+100))
+			s.place.Lights = append(s.place.Lights, light)
+			s.selectedLightIndex = len(s.place.Lights) - 1
+		}
+		for i, light := range s.place.Lights {
+			label := fmt.Sprintf("%d %s %s", i, light.Kind, light.Tag)
+			if i == s.selectedLightIndex {
+				ctx.Label("> " + label)
+			} else if ctx.Button(label) != 0 {
+				s.selectedLightIndex = i
+			}
+		}
+
+		if s.selectedLightIndex < 0 || s.selectedLightIndex >= len(s.place.Lights) {
+			return
+		}
+		light := s.place.Lights[s.selectedLightIndex]
+
+		ctx.SetLayoutRow([]int{labelWidth, -1}, 0)
+		ctx.Label("Kind")
+		if ctx.Button(light.Kind.String()) != 0 {
+			light.Kind = res.LightKinds[(int(light.Kind)+1)%len(res.LightKinds)]
+		}
+		ctx.Label("Tag")
+		if ctx.TextBox(&light.Tag)&debugui.ResponseSubmit != 0 {
+			ctx.SetFocus()
+		}
+
+		s.lightEdit = lightEdit{
+			X: float64(light.Point.X), Y: float64(light.Point.Y),
+			Color: newColorEdit(light.Color),
+		}
+		ctx.SetLayoutRow([]int{labelWidth, 60, 60}, 0)
+		ctx.Label("Point")
+		if ctx.Number(&s.lightEdit.X, 1, 0) != 0 || ctx.Number(&s.lightEdit.Y, 1, 0) != 0 {
+			light.Point = image.Pt(int(s.lightEdit.X), int(s.lightEdit.Y))
+		}
+		ctx.SetLayoutRow([]int{labelWidth, 35, 35, 35, 35}, 0)
+		ctx.Label("Color")
+		if colorNumbers(ctx, &s.lightEdit.Color) {
+			light.Color = s.lightEdit.Color.color()
+		}
+
+		ctx.SetLayoutRow([]int{labelWidth, -1}, 0)
+		ctx.Label("Radius")
+		ctx.Slider(&light.Radius, 1, 400, 1, 0)
+		ctx.Label("Flicker")
+		ctx.Slider(&light.Flicker, 0, 1, 0.05, 2)
+		if light.Kind == res.LightKindCone {
+			ctx.Label("Angle")
+			ctx.Slider(&light.Angle, -math.Pi, math.Pi, 0.05, 2)
+			ctx.Label("Spread")
+			ctx.Slider(&light.Spread, 0, math.Pi*2, 0.05, 2)
+		}
+
+		ctx.SetLayoutRow([]int{-1}, 0)
+		if ctx.Button("Delete Light") != 0 {
+			s.place.Lights = append(s.place.Lights[:s.selectedLightIndex], s.place.Lights[s.selectedLightIndex+1:]...)
+			s.selectedLightIndex = -1
+		}
+	})
+}
+
+// colorNumbers shows numbers for each channel of the edit's color, returning if any changed.
+func colorNumbers(ctx *debugui.Context, e *colorEdit) bool {
+	changed := false
+	for _, v := range []*float64{&e.R, &e.G, &e.B, &e.A} {
+		if ctx.Number(v, 1, 0) != 0 {
+			*v = math.Max(0, math.Min(255, *v))
+			changed = true
+		}
+	}
+	return changed
+}
+
+// newColorEdit makes an edit of the color.
+func newColorEdit(c color.NRGBA) colorEdit {
+	return colorEdit{R: float64(c.R), G: float64(c.G), B: float64(c.B), A: float64(c.A)}
+}
+
+// color returns the edit's color.
+func (e colorEdit) color() color.NRGBA {
+	return color.NRGBA{uint8(e.R), uint8(e.G), uint8(e.B), uint8(e.A)}
+}
+
+// drawLights marks where the place's lights are and how far they reach.
+func (s *State) drawLights(screen *ebiten.Image, op *ebiten.DrawImageOptions) {
+	for i, light := range s.place.Lights {
+		x, y := op.GeoM.Apply(float64(light.Point.X), float64(light.Point.Y))
+		r := float32(light.Radius * op.GeoM.Element(0, 0))
+		clr := light.Color
+		clr.A = 0x80
+		width := float32(1)
+		if i == s.selectedLightIndex {
+			width = 2
+		}
+		vector.StrokeCircle(screen, float32(x), float32(y), r, width, clr, true)
+		vector.DrawFilledCircle(screen, float32(x), float32(y), 3, light.Color, true)
+		if light.Kind == res.LightKindCone {
+			for _, a := range []float64{light.Angle - light.Spread/2, light.Angle + light.Spread/2} {
+				vector.StrokeLine(screen, float32(x), float32(y), float32(x+math.Cos(a)*float64(r)), float32(y+math.Sin(a)*float64(r)), width, clr, true)
+			}
+		}
+	}
+}
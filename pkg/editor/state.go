@@ -1,7 +1,6 @@
 package editor
 
 import (
-	"encoding/json"
 	"fmt"
 	"image"
 	"image/color"
@@ -14,6 +13,7 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/vector"
 	"github.com/kettek/ehh24/pkg/res"
 	"github.com/kettek/ehh24/pkg/statemachine"
+	"github.com/kettek/ehh24/pkg/transition"
 )
 
 // State is the editor state.
@@ -25,32 +25,60 @@ type State struct {
 	currentStax         string
 	selectedStaticIndex int
 	// TODO: Move this to a map struct
-	selectedFloorIndex   int
-	selectedPolygonIndex int
-	pendingPolygon       res.Polygon
-	place                res.Place
-	scale                float64
-	scrollX              float64
-	scrollY              float64
-	gridWidth            float64
-	gridHeight           float64
-	gridLock             bool
-	pendingFilename      string
+	selectedFloorIndex     int
+	selectedPolygonIndex   int
+	pendingPolygon         res.Polygon
+	place                  res.Place
+	scale                  float64
+	scrollX                float64
+	scrollY                float64
+	gridWidth              float64
+	gridHeight             float64
+	gridLock               bool
+	pendingFilename        string
+	pendingPopup           string
+	fileError              string // Why the last open or save failed, shown in the File window.
+	uiFocused              bool   // The last click was in a window, so a text box or number there may be taking keys.
+	bindings               Bindings
+	layers                 map[string]*layerView
+	activeLayer            int
+	inspectX, inspectY     float64
+	bounds                 [4]float64 // Place bounds being edited, as min x, min y, max x, max y.
+	selectedLightIndex     int
+	lightEdit              lightEdit
+	ambientEdit            colorEdit
+	selectedEmitterIndex   int
+	emitterEdit            emitterEdit
+	selectedEffectIndex    int
+	effectParams           [maxEffectParams]float64
+	selectedContainerIndex int
+	containerCapacity      float64
 	//
 	pressX, pressY int
 }
 
 // NewState creates a new editor state.
 func NewState() *State {
+	bindings, err := ReadBindings()
+	if err != nil {
+		fmt.Println(err)
+	}
 	return &State{
-		place:       res.MakePlace(),
-		ui:          debugui.New(),
-		tool:        &ToolNone{},
-		windowAreas: make(map[string]image.Rectangle),
-		scale:       3,
-		gridWidth:   19,
-		gridHeight:  9,
-		gridLock:    true,
+		bindings:               bindings,
+		layers:                 make(map[string]*layerView),
+		activeLayer:            -1,
+		selectedLightIndex:     -1,
+		selectedEmitterIndex:   -1,
+		selectedEffectIndex:    -1,
+		selectedContainerIndex: -1,
+		place:                  res.MakePlace(),
+		ui:                     debugui.New(),
+		tool:                   &ToolNone{},
+		windowAreas:            make(map[string]image.Rectangle),
+		scale:                  3,
+		gridWidth:              19,
+		gridHeight:             9,
+		gridLock:               true,
 	}
 }
 
@@ -75,6 +103,11 @@ func (s *State) Update() statemachine.State {
 		}
 
 		s.windowOptions(ctx)
+		s.windowLayers(ctx)
+		s.windowLights(ctx)
+		s.windowEmitters(ctx)
+		s.windowEffects(ctx)
+		s.windowContainers(ctx)
 
 		s.windowFile(ctx)
 	})
@@ -87,19 +120,28 @@ func (s *State) Update() statemachine.State {
 			break
 		}
 	}
+	// debugui keeps a text box focused until Enter or a click elsewhere.
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		s.uiFocused = inBounds
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		s.uiFocused = false
+	}
 	if !inBounds {
 		cx, cy := s.CursorPosition()
 		s.tool.Move(s, cx, cy)
+		s.updateShortcuts()
 		// Alright, let's lcick on mappe
-		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-			s.tool.Button(s, ebiten.MouseButtonLeft, true)
-		} else if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
-			s.tool.Button(s, ebiten.MouseButtonLeft, false)
-		}
-		if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonRight) {
-			s.tool.Button(s, ebiten.MouseButtonRight, false)
-		} else if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
-			s.tool.Button(s, ebiten.MouseButtonRight, true)
+		if !s.toolLocked() {
+			if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+				s.tool.Button(s, ebiten.MouseButtonLeft, true)
+			} else if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
+				s.tool.Button(s, ebiten.MouseButtonLeft, false)
+			}
+			if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonRight) {
+				s.tool.Button(s, ebiten.MouseButtonRight, false)
+			} else if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+				s.tool.Button(s, ebiten.MouseButtonRight, true)
+			}
 		}
 		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonMiddle) {
 			s.pressX, s.pressY = x, y
@@ -117,18 +159,19 @@ func (s *State) Update() statemachine.State {
 		s.selectedPolygonIndex = -1
 		s.currentStax = ""
 		s.tool.Reset()
-	} else if inpututil.IsKeyJustReleased(ebiten.KeyDelete) {
+	} else if inpututil.IsKeyJustReleased(ebiten.KeyDelete) && !s.toolLocked() {
 		if s.tool.Name() == (ToolFloor{}).Name() {
 			if s.selectedFloorIndex >= 0 && s.selectedFloorIndex < len(s.place.Floor) {
 				s.place.Floor = append(s.place.Floor[:s.selectedFloorIndex], s.place.Floor[s.selectedFloorIndex+1:]...)
 			}
 		} else if s.tool.Name() == (ToolPolygon{}).Name() || s.tool.Name() == (ToolPolygonSelect{}).Name() {
-			if s.selectedPolygonIndex >= 0 && s.selectedPolygonIndex < len(s.place.Polygons) {
+			if s.selectedPolygonIndex >= 0 && s.selectedPolygonIndex < len(s.place.Polygons) && !s.polygonLocked(s.place.Polygons[s.selectedPolygonIndex]) {
 				s.place.Polygons = append(s.place.Polygons[:s.selectedPolygonIndex], s.place.Polygons[s.selectedPolygonIndex+1:]...)
 			}
 		} else if s.tool.Name() == (ToolStatic{}).Name() {
-			if s.selectedStaticIndex >= 0 && s.selectedStaticIndex < len(s.place.Statics) {
-				s.place.Statics = append(s.place.Statics[:s.selectedStaticIndex], s.place.Statics[s.selectedStaticIndex+1:]...)
+			statics := s.currentStatics()
+			if s.selectedStaticIndex >= 0 && s.selectedStaticIndex < len(*statics) {
+				*statics = append((*statics)[:s.selectedStaticIndex], (*statics)[s.selectedStaticIndex+1:]...)
 			}
 		}
 	}
@@ -142,11 +185,59 @@ func (s *State) Draw(screen *ebiten.Image) {
 	op.GeoM.Translate(-s.scrollX, -s.scrollY)
 	op.GeoM.Scale(s.scale, s.scale)
 
-	for _, s := range s.place.Floor {
-		s.Draw(screen, op)
+	s.drawStatics(screen, op, s.layer(layerFloors), 1, s.place.Floor)
+
+	if grid := s.layer(layerGrid); grid.Visible {
+		s.drawGrid(screen, grid.Opacity)
+	}
+
+	s.drawBounds(screen, op)
+
+	s.drawStatics(screen, op, s.layer(layerStatics), 1, s.place.Statics)
+
+	for _, layer := range s.place.Layers {
+		s.drawStatics(screen, op, s.layer(placeLayerName(layer)), layer.Opacity, layer.Statics)
 	}
 
-	// Grid.
+	for _, p := range s.place.Polygons {
+		if !s.polygonVisible(p) {
+			continue
+		}
+		pop := &ebiten.DrawImageOptions{}
+		pop.GeoM = op.GeoM
+		pop.ColorScale.ScaleAlpha(float32(s.polygonOpacity(p)))
+		p.Draw(screen, pop)
+	}
+
+	s.drawLights(screen, op)
+	s.drawEmitters(screen, op)
+
+	s.tool.Draw(screen, op)
+
+	s.ui.Draw(screen)
+}
+
+// drawStatics draws the given statics if their layer is visible.
+func (s *State) drawStatics(screen *ebiten.Image, op *ebiten.DrawImageOptions, l *layerView, opacity float64, statics []*res.Static) {
+	if !l.Visible {
+		return
+	}
+	sop := &ebiten.DrawImageOptions{}
+	sop.GeoM = op.GeoM
+	sop.ColorScale.ScaleAlpha(float32(l.Opacity * opacity))
+	for _, st := range statics {
+		st.Draw(screen, sop)
+	}
+}
+
+// drawGrid draws the grid lines.
+func (s *State) drawGrid(screen *ebiten.Image, opacity float64) {
+	clr := color.RGBA{0x40, 0x40, 0x40, 0x40}
+	clr.R = uint8(float64(clr.R) * opacity)
+	clr.G = uint8(float64(clr.G) * opacity)
+	clr.B = uint8(float64(clr.B) * opacity)
+	clr.A = uint8(float64(clr.A) * opacity)
+
 	rows := int(float64(screen.Bounds().Dy()) / s.gridHeight)
 	cols := int(float64(screen.Bounds().Dx()) / s.gridWidth)
 
@@ -163,7 +254,7 @@ func (s *State) Draw(screen *ebiten.Image) {
 		x2 *= float32(s.scale)
 		y1 *= float32(s.scale)
 		y2 *= float32(s.scale)
-		vector.StrokeLine(screen, x1, y1, x2, y2, 1, color.RGBA{0x40, 0x40, 0x40, 0x40}, true)
+		vector.StrokeLine(screen, x1, y1, x2, y2, 1, clr, true)
 	}
 	for y := 1; y < rows; y++ {
 		x1 := float32(0)
@@ -178,20 +269,8 @@ func (s *State) Draw(screen *ebiten.Image) {
 		x2 *= float32(s.scale)
 		y1 *= float32(s.scale)
 		y2 *= float32(s.scale)
-		vector.StrokeLine(screen, x1, y1, x2, y2, 1, color.RGBA{0x40, 0x40, 0x40, 0x40}, true)
+		vector.StrokeLine(screen, x1, y1, x2, y2, 1, clr, true)
 	}
-
-	for _, s := range s.place.Statics {
-		s.Draw(screen, op)
-	}
-
-	for _, p := range s.place.Polygons {
-		p.Draw(screen, op)
-	}
-
-	s.tool.Draw(screen, op)
-
-	s.ui.Draw(screen)
 }
 
 // Layout does a layout.
@@ -199,30 +278,60 @@ func (s *State) Layout(ow, oh int) (int, int) {
 	return ow, oh
 }
 
+// Unscaled keeps the editor at the window's full resolution.
+func (s *State) Unscaled() {}
+
 func (s *State) windowFile(ctx *debugui.Context) {
 	ctx.Window("File", posFile.Rect(), func(resp debugui.Response, layout debugui.Layout) {
 		s.windowAreas["File"] = layout.Rect
 		ctx.SetLayoutRow([]int{50, 50, 50}, 0)
 		if ctx.Button("New") != 0 {
 			s.place = res.MakePlace()
+			s.activeLayer = -1
+			s.selectedLightIndex = -1
+			s.selectedEmitterIndex = -1
+			s.selectedEffectIndex = -1
+			s.selectedContainerIndex = -1
 		}
 		ctx.Popup("Open", func(resp debugui.Response, layout debugui.Layout) {
 			s.windowAreas["Popup"] = layout.Rect
 			type placie struct {
-				Key  string
-				Name string
+				Key    string
+				Name   string
+				Source string
+				Err    error
 			}
 			var places []placie
-			for k, place := range res.Places {
-				places = append(places, placie{Key: k, Name: place.Name})
+			for _, k := range res.PlaceNames() {
+				place, err := res.GetPlace(k)
+				name := place.Name
+				if err != nil {
+					// Broken places are still listed, so it's clear why they can't be opened.
+					name = k
+				}
+				places = append(places, placie{Key: k, Name: name, Source: res.SourceOf(k + ".json"), Err: err})
 			}
 			slices.SortFunc(places, func(a, b placie) int {
 				return strings.Compare(a.Name, b.Name)
 			})
 
 			for _, place := range places {
-				if ctx.Button(place.Name) != 0 {
-					s.place = res.Places[place.Key]
+				label := fmt.Sprintf("%s (%s)\x00%s", place.Name, place.Source, place.Key)
+				if place.Err != nil {
+					label = fmt.Sprintf("%s (%s, broken)\x00%s", place.Name, place.Source, place.Key)
+				}
+				if ctx.Button(label) != 0 {
+					if place.Err != nil {
+						s.fileError = place.Err.Error()
+						continue
+					}
+					s.fileError = ""
+					s.place, _ = res.GetPlace(place.Key)
+					s.activeLayer = -1
+					s.selectedLightIndex = -1
+					s.selectedEmitterIndex = -1
+					s.selectedEffectIndex = -1
+					s.selectedContainerIndex = -1
 					s.pendingFilename = strings.TrimPrefix(place.Key, "places/")
 				}
 			}
@@ -236,23 +345,42 @@ func (s *State) windowFile(ctx *debugui.Context) {
 			}
 			ctx.SetLayoutRow([]int{-1}, 0)
 			if ctx.Button("Save") != 0 {
-				if d, err := json.Marshal(s.place); err != nil {
-					fmt.Println(err)
-				} else {
-					res.WriteFile("places/"+s.pendingFilename+".json", d)
-					res.RefreshAssets()
-				}
+				s.save()
 			}
 		})
+		if s.pendingPopup != "" {
+			ctx.OpenPopup(s.pendingPopup)
+			s.pendingPopup = ""
+		}
 		if ctx.Button("Open") != 0 {
 			ctx.OpenPopup("Open")
 		}
 		if ctx.Button("Save...") != 0 {
 			ctx.OpenPopup("Save")
 		}
+		if s.fileError != "" {
+			ctx.SetLayoutRow([]int{-1}, 0)
+			ctx.Text(s.fileError)
+		}
 	})
 }
 
+// save writes the place to its pending filename and refreshes assets.
+func (s *State) save() {
+	d, err := res.MarshalPlace(s.place)
+	if err == nil {
+		err = res.WriteFile("places/"+s.pendingFilename+".json", d)
+	}
+	if err == nil {
+		err = res.RefreshAssets()
+	}
+	if err != nil {
+		s.fileError = err.Error()
+		return
+	}
+	s.fileError = ""
+}
+
 func (s *State) windowTools(ctx *debugui.Context) {
 	ctx.Window("Tools", posTools.Rect(), func(resp debugui.Response, layout debugui.Layout) {
 		s.windowAreas["Tools"] = layout.Rect
@@ -276,28 +404,22 @@ func (s *State) windowStaxies(ctx *debugui.Context) {
 		s.windowAreas["ToolItem"] = layout.Rect
 
 		if ctx.Header("Current Static", true) != 0 {
-			if s.selectedStaticIndex >= 0 && s.selectedStaticIndex < len(s.place.Statics) {
-				stax := s.place.Statics[s.selectedStaticIndex]
+			statics := s.currentStatics()
+			if s.selectedStaticIndex >= 0 && s.selectedStaticIndex < len(*statics) {
 				ctx.Label(fmt.Sprintf("Index: %d", s.selectedStaticIndex))
-
-				ctx.SetLayoutRow([]int{labelWidth, -1}, 0)
-				ctx.Label("Tag")
-				if ctx.TextBox(&stax.Tag)&debugui.ResponseSubmit != 0 {
-					ctx.SetFocus()
+				s.inspectStatic(ctx, (*statics)[s.selectedStaticIndex])
+				if ctx.Button("Delete") != 0 {
+					*statics = append((*statics)[:s.selectedStaticIndex], (*statics)[s.selectedStaticIndex+1:]...)
+					s.selectedStaticIndex = -1
 				}
-				ctx.SetLayoutRow([]int{-1}, 0)
-
-				s.place.Statics[s.selectedStaticIndex] = stax
 			}
 		}
 	})
 	ctx.Window("Staxii", posToolItemList.Rect(), func(resp debugui.Response, layout debugui.Layout) {
 		s.windowAreas["ToolItemList"] = layout.Rect
-		for _, stax := range s.sortedStaxii(res.Staxii) {
-			if ctx.Button(stax.Name) != 0 {
-				s.tool.(*ToolStatic).pending.Name = stax.Name
-			}
-		}
+		s.staxList(ctx, func(name string) {
+			s.tool.(*ToolStatic).pending.Name = name
+		})
 	})
 }
 
@@ -306,9 +428,8 @@ func (s *State) windowFloors(ctx *debugui.Context) {
 		s.windowAreas["ToolItem"] = layout.Rect
 		if ctx.Header("Current Floor", true) != 0 {
 			if s.selectedFloorIndex >= 0 && s.selectedFloorIndex < len(s.place.Floor) {
-				stax := s.place.Floor[s.selectedFloorIndex]
 				ctx.Label(fmt.Sprintf("Index: %d", s.selectedFloorIndex))
-				s.place.Floor[s.selectedFloorIndex] = stax
+				s.inspectStatic(ctx, s.place.Floor[s.selectedFloorIndex])
 				if ctx.Button("Delete") != 0 {
 					s.place.Floor = append(s.place.Floor[:s.selectedFloorIndex], s.place.Floor[s.selectedFloorIndex+1:]...)
 				}
@@ -317,11 +438,9 @@ func (s *State) windowFloors(ctx *debugui.Context) {
 	})
 	ctx.Window("Staxii", posToolItemList.Rect(), func(resp debugui.Response, layout debugui.Layout) {
 		s.windowAreas["ToolItemList"] = layout.Rect
-		for _, stax := range s.sortedStaxii(res.Staxii) {
-			if ctx.Button(stax.Name) != 0 {
-				s.tool.(*ToolFloor).pending.Name = stax.Name
-			}
-		}
+		s.staxList(ctx, func(name string) {
+			s.tool.(*ToolFloor).pending.Name = name
+		})
 	})
 }
 
@@ -417,6 +536,14 @@ func (s *State) windowPolygons(ctx *debugui.Context) {
 						ctx.SetFocus()
 					}
 					ctx.SetLayoutRow([]int{-1}, 0)
+					if polygon.SubKind == res.PolygonTriggerTravel {
+						ctx.SetLayoutRow([]int{labelWidth, -1}, 0)
+						ctx.Label("Transition")
+						if ctx.Button(travelTransitionLabel(polygon.Transition)) != 0 {
+							polygon.Transition = nextTravelTransition(polygon.Transition)
+						}
+						ctx.SetLayoutRow([]int{-1}, 0)
+					}
 				}
 				ctx.SetLayoutRow([]int{labelWidth, -1}, 0)
 				ctx.Label("ScriptFile")
@@ -513,9 +640,56 @@ func (s *State) windowOptions(ctx *debugui.Context) {
 			ctx.Number(&s.gridHeight, 1, 1)
 		}
 		ctx.SetLayoutRow([]int{-1}, 0)
+
+		s.bounds = [4]float64{float64(s.place.Bounds.Min.X), float64(s.place.Bounds.Min.Y), float64(s.place.Bounds.Max.X), float64(s.place.Bounds.Max.Y)}
+		ctx.SetLayoutRow([]int{40, 40, 40, 40, 40}, 0)
+		ctx.Label("Bounds")
+		changed := false
+		for i := range s.bounds {
+			if ctx.Number(&s.bounds[i], 1, 0) != 0 {
+				changed = true
+			}
+		}
+		if changed {
+			s.place.Bounds = image.Rect(int(s.bounds[0]), int(s.bounds[1]), int(s.bounds[2]), int(s.bounds[3]))
+		}
+		ctx.SetLayoutRow([]int{-1}, 0)
+		if ctx.Button("Fit Bounds") != 0 {
+			s.place.Bounds = s.place.Extents()
+		}
+		ctx.Checkbox("Limited View", &s.place.LimitedView)
+
+		ctx.SetLayoutRow([]int{60, -1}, 0)
+		ctx.Label("Weather")
+		if ctx.Button(s.place.Weather.Kind.String()) != 0 {
+			s.place.Weather.Kind = res.WeatherKinds[(int(s.place.Weather.Kind)+1)%len(res.WeatherKinds)]
+		}
+		ctx.Label("Intensity")
+		ctx.Slider(&s.place.Weather.Intensity, 0, 1, 0.05, 2)
+		ctx.Label("Wind")
+		ctx.Slider(&s.place.Weather.Wind, -2, 2, 0.05, 2)
+		ctx.Label("Music")
+		if ctx.TextBox(&s.place.Music)&debugui.ResponseSubmit != 0 {
+			ctx.SetFocus()
+		}
+		ctx.Label("Ambience")
+		if ctx.TextBox(&s.place.Ambience)&debugui.ResponseSubmit != 0 {
+			ctx.SetFocus()
+		}
+		ctx.SetLayoutRow([]int{-1}, 0)
 	})
 }
 
+// drawBounds outlines the place's camera bounds.
+func (s *State) drawBounds(screen *ebiten.Image, op *ebiten.DrawImageOptions) {
+	if s.place.Bounds.Empty() {
+		return
+	}
+	x1, y1 := op.GeoM.Apply(float64(s.place.Bounds.Min.X), float64(s.place.Bounds.Min.Y))
+	x2, y2 := op.GeoM.Apply(float64(s.place.Bounds.Max.X), float64(s.place.Bounds.Max.Y))
+	vector.StrokeRect(screen, float32(x1), float32(y1), float32(x2-x1), float32(y2-y1), 1, color.RGBA{0x80, 0x40, 0x80, 0x80}, false)
+}
+
 // CursorPosition returns the cursor position.
 func (s *State) CursorPosition() (int, int) {
 	x, y := ebiten.CursorPosition()
@@ -553,6 +727,11 @@ var posTools = posSize{X: posFile.X + posFile.W + 10, Y: 10, W: 440, H: 54}
 var posToolItem = posSize{X: 10, Y: posFile.Y + posFile.H + 10, W: 200, H: 300}
 var posToolItemList = posSize{X: 10, Y: posToolItem.Y + posToolItem.H + 10, W: 200, H: 325}
 var posOptions = posSize{X: 1060, Y: 10, W: 200, H: 300}
+var posLights = posSize{X: posOptions.X - 210, Y: 10, W: 200, H: 300}
+var posEmitters = posSize{X: posLights.X, Y: posLights.Y + posLights.H + 10, W: 200, H: 380}
+var posEffects = posSize{X: posLights.X - 210, Y: 10, W: 200, H: 300}
+var posContainers = posSize{X: posEffects.X, Y: posEffects.Y + posEffects.H + 10, W: 200, H: 300}
+var posLayers = posSize{X: 1060, Y: posOptions.Y + posOptions.H + 10, W: 200, H: 380}
 
 const labelWidth = 45
 
@@ -566,3 +745,24 @@ type posSize struct {
 func (p posSize) Rect() image.Rectangle {
 	return image.Rect(p.X, p.Y, p.X+p.W, p.Y+p.H)
 }
+
+// travelTransitionLabel names a travel trigger's transition, which is an iris when left empty.
+func travelTransitionLabel(kind string) string {
+	if kind == "" {
+		return "Default"
+	}
+	return kind
+}
+
+// nextTravelTransition cycles a travel trigger's transition through the kinds and back to the default.
+func nextTravelTransition(kind string) string {
+	if kind == "" {
+		return transition.Kinds[0].String()
+	}
+	for i, k := range transition.Kinds {
+		if k.String() == kind && i+1 < len(transition.Kinds) {
+			return transition.Kinds[i+1].String()
+		}
+	}
+	return ""
+}
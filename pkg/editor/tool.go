@@ -109,6 +109,9 @@ func (t *ToolPolygonSelect) Button(s *State, b ebiten.MouseButton, pressed bool)
 	if b == ebiten.MouseButtonLeft && pressed {
 		s.selectedPolygonIndex = -1
 		for i, poly := range s.place.Polygons {
+			if !s.polygonVisible(poly) || s.polygonLocked(poly) {
+				continue
+			}
 			if poly.ContainsPoint(float64(t.x), float64(t.y)) {
 				s.selectedPolygonIndex = i
 				break
@@ -149,7 +152,8 @@ func (t ToolStatic) Name() string {
 // Button handles mouse button presses.
 func (t *ToolStatic) Button(s *State, b ebiten.MouseButton, pressed bool) {
 	if b == ebiten.MouseButtonRight && pressed {
-		s.place.Statics = append(s.place.Statics, &res.Static{
+		statics := s.currentStatics()
+		*statics = append(*statics, &res.Static{
 			Name:  t.pending.Name,
 			Point: image.Pt(t.pending.Point.X, t.pending.Point.Y),
 		})
@@ -157,23 +161,24 @@ func (t *ToolStatic) Button(s *State, b ebiten.MouseButton, pressed bool) {
 		if pressed {
 			t.draggingIndex = -1
 			s.selectedStaticIndex = -1
-			for i, stax := range s.place.Statics {
+			statics := *s.currentStatics()
+			for i, stax := range statics {
 				if stack, ok := res.Staxii[stax.Name]; ok {
 					x1 := stax.Point.X - stack.Stax.SliceWidth/2
 					y1 := stax.Point.Y - stack.Stax.SliceHeight
 					x2 := stax.Point.X + stack.Stax.SliceWidth/2
 					y2 := stax.Point.Y
 					if t.pending.Point.X >= x1 && t.pending.Point.X <= x2 && t.pending.Point.Y >= y1 && t.pending.Point.Y <= y2 {
-						t.dragging = *s.place.Statics[i]
+						t.dragging = *statics[i]
 						t.draggingIndex = i
 						s.selectedStaticIndex = i
 						break
 					}
 				}
 			}
-		} else if t.draggingIndex != -1 {
-			s.place.Statics[t.draggingIndex].Point.X = t.dragging.Point.X
-			s.place.Statics[t.draggingIndex].Point.Y = t.dragging.Point.Y
+		} else if statics := *s.currentStatics(); t.draggingIndex >= 0 && t.draggingIndex < len(statics) {
+			statics[t.draggingIndex].Point.X = t.dragging.Point.X
+			statics[t.draggingIndex].Point.Y = t.dragging.Point.Y
 			t.draggingIndex = -1
 		}
 	}
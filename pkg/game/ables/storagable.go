@@ -3,15 +3,17 @@ package ables
 // Storagable can store stuff.
 type Storagable []StorageItem
 
-// AddItem adds an item to storage the storage, incrementing count if existing.
-func (s *Storagable) AddItem(name string, tag string) {
-	for i, item := range *s {
-		if item.Tag == tag {
-			(*s)[i].Count++
-			return
+// AddItem adds an item to storage the storage, incrementing count if existing and stackable.
+func (s *Storagable) AddItem(tag string, stackable bool) {
+	if stackable {
+		for i, item := range *s {
+			if item.Tag == tag {
+				(*s)[i].Count++
+				return
+			}
 		}
 	}
-	*s = append(*s, StorageItem{Name: name, Tag: tag, Count: 1})
+	*s = append(*s, StorageItem{Tag: tag, Count: 1})
 }
 
 // RemoveItem removes an item from storage, decrementing count if existing.
@@ -28,18 +30,17 @@ func (s *Storagable) RemoveItem(tag string) {
 }
 
 // HasItem returns true if the storage has the item.
-func (s Storagable) HasItem(name string) bool {
+func (s Storagable) HasItem(tag string) bool {
 	for _, item := range s {
-		if item.Name == name {
+		if item.Tag == tag {
 			return true
 		}
 	}
 	return false
 }
 
-// StorageItem is an item in storage.
+// StorageItem is an item in storage. Its tag is the ID of its item definition.
 type StorageItem struct {
-	Name  string
 	Tag   string
 	Count int
 }
@@ -3,8 +3,13 @@ package game
 import (
 	"fmt"
 	"math"
+
+	"github.com/kettek/ehh24/pkg/config"
 )
 
+// footstepTicks is how many ticks of walking there are between footsteps, being half a swing of the legs.
+const footstepTicks = 31
+
 // Action represents an action that can be applied to a thinger, optionally looping with Done() checks.
 type Action interface {
 	Apply(t *Thinger) []Change
@@ -44,7 +49,7 @@ func (a *ActionLook) Apply(t *Thinger) (c []Change) {
 		}
 	}
 
-	c = append(c, &ChangeVisibilityOverlay{
+	c = append(c, &ChangeView{
 		X:     t.X(),
 		Y:     t.Y() - float64(t.stax.Stax.SliceHeight)/2,
 		Angle: math.Atan2(t.lookY+0.4, t.lookX), // Fix this hardcoded 0.4... it's the offset we need for eye position
@@ -98,6 +103,14 @@ func (a *ActionMoveTo) Apply(t *Thinger) (c []Change) {
 	}
 	t.walking = true
 	t.walkTicker++
+	if t.walkTicker%footstepTicks == 0 {
+		c = append(c, &ChangeSound{
+			Name:       "step",
+			X:          t.X(),
+			Y:          t.Y(),
+			Positional: true,
+		})
+	}
 	if dx < 0 {
 		t.Animation("left")
 	} else if dx > 0 {
@@ -115,7 +128,7 @@ func (a *ActionMoveTo) Apply(t *Thinger) (c []Change) {
 		Y:       t.Y() + dy/dist*a.Speed*0.6,
 	})
 
-	c = append(c, &ChangeVisibilityOverlay{
+	c = append(c, &ChangeView{
 		X:     t.X(),
 		Y:     t.Y() - float64(t.stax.Stax.SliceHeight)/2,
 		Angle: math.Atan2(t.lookY+0.4, t.lookX), // Fix this hardcoded 0.4... it's the offset we need for eye position
@@ -146,6 +159,12 @@ func (a *ActionPickup) Apply(t *Thinger) []Change {
 			&ChangeAcquireItem{
 				Tag: a.Target,
 			},
+			&ChangeSound{
+				Name:       "pickup",
+				X:          t.X(),
+				Y:          t.Y(),
+				Positional: true,
+			},
 		}
 	}
 	// Otherwise...
@@ -173,6 +192,11 @@ func (a *ActionUse) Apply(t *Thinger) (c []Change) {
 		a.done = true
 		c = append(c, &ChangeUse{
 			Tag: a.Target,
+		}, &ChangeSound{
+			Name:       "use",
+			X:          a.X,
+			Y:          a.Y,
+			Positional: true,
 		})
 		if a.Item != "" {
 			fmt.Println("remove", a.Item)
@@ -220,11 +244,12 @@ func (a *ActionFace) Done() bool {
 }
 
 type ActionTravel struct {
-	Place string
+	Place      string
+	Transition string // Kind of transition, such as "Wipe". Empty is an iris onto the player.
 }
 
 func (a *ActionTravel) Apply(t *Thinger) []Change {
-	return []Change{&ChangeTravel{Place: a.Place}}
+	return []Change{&ChangeTravel{Place: a.Place, Transition: a.Transition}, &ChangeSound{Name: "travel"}}
 }
 
 func (a *ActionTravel) Done() bool {
@@ -233,11 +258,20 @@ func (a *ActionTravel) Done() bool {
 
 type ActionMonologue struct {
 	Text  string
-	Timer int
+	Timer int     // Ticks the text stays up once it is all typed out.
+	typed float64 // Characters typed out so far.
 }
 
 func (a *ActionMonologue) Apply(t *Thinger) []Change {
 	t.monologue = a.Text
+	// Type it out at the text speed, if there is one, holding off the timer until it is done.
+	if speed := config.Current.TextSpeed; speed > 0 {
+		if runes := []rune(a.Text); int(a.typed) < len(runes) {
+			a.typed += speed
+			t.monologue = string(runes[:min(int(a.typed), len(runes))])
+			return nil
+		}
+	}
 	a.Timer--
 	if a.Timer <= 0 {
 		t.monologue = ""
@@ -0,0 +1,140 @@
+package game
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/ehh24/pkg/config"
+	input "github.com/quasilyte/ebitengine-input"
+)
+
+// Our inputs for zooming the camera.
+const (
+	InputZoomIn input.Action = iota
+	InputZoomOut
+)
+
+// cameraBindings are the config bindings of our inputs.
+var cameraBindings = map[input.Action]string{
+	InputZoomIn:  "ZoomIn",
+	InputZoomOut: "ZoomOut",
+}
+
+// Camera limits and such.
+const (
+	cameraMinZoom  = 0.5
+	cameraMaxZoom  = 3
+	cameraZoomStep = 0.25
+)
+
+// Camera is our view into za warudo. It follows a target, stays within the place's bounds, and zooms on top of the context's base zoom.
+type Camera struct {
+	X, Y       float64 // Center of the view, in world coordinates.
+	Zoom       float64
+	TargetZoom float64
+	Target     string  // Tag of the referable to follow.
+	Smoothing  float64 // How much of the way to the target is covered each tick.
+	snap       bool
+	input      *input.Handler
+}
+
+// NewCamera makes a camera that follows the referable with the given tag.
+func NewCamera(insys *input.System, target string) *Camera {
+	return &Camera{
+		Zoom:       1,
+		TargetZoom: 1,
+		Target:     target,
+		Smoothing:  0.1,
+		snap:       true,
+		input:      insys.NewHandler(0, config.Keymap(cameraBindings)),
+	}
+}
+
+// Remap rebinds the camera's inputs to the config's bindings.
+func (c *Camera) Remap() {
+	c.input.Remap(config.Keymap(cameraBindings))
+}
+
+// Snap makes the camera jump straight to its target on the next update, such as after traveling.
+func (c *Camera) Snap() {
+	c.snap = true
+}
+
+// ZoomBy changes the target zoom by the given number of steps.
+func (c *Camera) ZoomBy(steps float64) {
+	c.TargetZoom = math.Max(cameraMinZoom, math.Min(cameraMaxZoom, c.TargetZoom+steps*cameraZoomStep))
+}
+
+// Update zooms, follows the target, and keeps the view within the place's bounds.
+func (c *Camera) Update(ctx *ContextGame) {
+	if c.input.ActionIsJustPressed(InputZoomIn) {
+		c.ZoomBy(1)
+	} else if c.input.ActionIsJustPressed(InputZoomOut) {
+		c.ZoomBy(-1)
+	}
+
+	x, y := c.X, c.Y
+	if t, ok := ctx.Referables.ByFirstTag(c.Target).(Drawable); ok {
+		x, y = t.X(), t.Y()
+	}
+
+	if c.snap {
+		c.snap = false
+		c.Zoom = c.TargetZoom
+		c.X, c.Y = x, y
+	} else {
+		c.Zoom += (c.TargetZoom - c.Zoom) * c.Smoothing
+		c.X += (x - c.X) * c.Smoothing
+		c.Y += (y - c.Y) * c.Smoothing
+	}
+
+	if ctx.Place != nil {
+		c.X, c.Y = c.clamp(ctx, ctx.Place.Bounds, c.X, c.Y)
+	}
+}
+
+// clamp keeps the view within bounds, centering on them along any axis they are smaller than the view in.
+func (c *Camera) clamp(ctx *ContextGame, bounds image.Rectangle, x, y float64) (float64, float64) {
+	if bounds.Empty() {
+		return x, y
+	}
+	w, h := c.ViewSize(ctx)
+	clampAxis := func(v, lo, hi, size float64) float64 {
+		if hi-lo <= size {
+			return (lo + hi) / 2
+		}
+		return math.Max(lo+size/2, math.Min(hi-size/2, v))
+	}
+	x = clampAxis(x, float64(bounds.Min.X), float64(bounds.Max.X), w)
+	y = clampAxis(y, float64(bounds.Min.Y), float64(bounds.Max.Y), h)
+	return x, y
+}
+
+// ViewSize returns the size of the view in world coordinates.
+func (c *Camera) ViewSize(ctx *ContextGame) (float64, float64) {
+	scale := ctx.Zoom * c.Zoom
+	return ctx.Width / scale, ctx.Height / scale
+}
+
+// GeoM returns the transform from world to screen coordinates. The translation is snapped to whole screen pixels so slices don't shimmer as the camera eases.
+func (c *Camera) GeoM(ctx *ContextGame) ebiten.GeoM {
+	scale := ctx.Zoom * c.Zoom
+	var geom ebiten.GeoM
+	geom.Scale(scale, scale)
+	geom.Translate(math.Round(ctx.Width/2-c.X*scale), math.Round(ctx.Height/2-c.Y*scale))
+	return geom
+}
+
+// ScreenToWorld converts screen coordinates to world coordinates.
+func (c *Camera) ScreenToWorld(ctx *ContextGame, x, y float64) (float64, float64) {
+	geom := c.GeoM(ctx)
+	geom.Invert()
+	return geom.Apply(x, y)
+}
+
+// WorldToScreen converts world coordinates to screen coordinates.
+func (c *Camera) WorldToScreen(ctx *ContextGame, x, y float64) (float64, float64) {
+	geom := c.GeoM(ctx)
+	return geom.Apply(x, y)
+}
@@ -1,10 +1,12 @@
 package game
 
 import (
+	"fmt"
 	"strings"
 
-	"github.com/kettek/ehh24/pkg/game/ables"
+	"github.com/kettek/ehh24/pkg/audio"
 	"github.com/kettek/ehh24/pkg/res"
+	"github.com/kettek/ehh24/pkg/transition"
 )
 
 // Change is a requested change to the game state originating from an action.
@@ -12,26 +14,51 @@ type Change interface {
 	Apply(g *ContextGame)
 }
 
-// ChangeVisibilityOverlay changes the visibility overlay.
-type ChangeVisibilityOverlay struct {
+// ChangeView moves the player's view to X, Y and turns it towards Angle.
+type ChangeView struct {
 	X, Y  float64
 	Angle float64
 }
 
-// Apply applies changes to the visibility overlay.
-func (c *ChangeVisibilityOverlay) Apply(ctx *ContextGame) {
-	if v, ok := ctx.Referables.ByFirstTag("visibility").(*VisibilityOverlay); ok {
-		v.SetX(c.X)
-		v.SetY(c.Y)
-		v.TargetAngle = c.Angle
+// Apply applies changes to the view's lights.
+func (c *ChangeView) Apply(ctx *ContextGame) {
+	for _, l := range ctx.Lighting.View {
+		l.X = c.X
+		l.Y = c.Y
+		l.TargetAngle = c.Angle
 	}
 }
 
+// travelTicks is how long each half of a travel transition takes.
+const travelTicks = 30
+
+// ChangeTravel travels to another place, as "place" or "place:area" to enter at an area, once a transition has covered the screen.
 type ChangeTravel struct {
-	Place string
+	Place      string
+	Transition string // Kind of transition, such as "Wipe". Empty is an iris onto the player.
 }
 
 func (c *ChangeTravel) Apply(ctx *ContextGame) {
+	if ctx.Transition != nil {
+		return
+	}
+	kind := transition.KindIris
+	if c.Transition != "" {
+		if err := kind.UnmarshalText([]byte(c.Transition)); err != nil {
+			fmt.Println("bad transition", err)
+			kind = transition.KindIris
+		}
+	}
+	t := transition.New(kind, travelTicks)
+	t.Scale = ctx.Zoom
+	t.OnMidpoint = func() {
+		c.travel(ctx)
+	}
+	ctx.Transition = t
+}
+
+// travel does the actual traveling, out of sight.
+func (c *ChangeTravel) travel(ctx *ContextGame) {
 	parts := strings.SplitN(c.Place, ":", 2)
 	var placeName string
 	var enter string
@@ -47,49 +74,7 @@ func (c *ChangeTravel) Apply(ctx *ContextGame) {
 		return
 	}
 
-	if _, ok := ctx.Places[placeName]; ok {
-		ctx.Place = ctx.Places[placeName]
-	} else {
-		place := NewPlace(placeName)
-		ctx.Places[placeName] = place
-		ctx.Place = place
-
-		// Alright, hacky time...
-		if placeName == "outside" {
-			roboid := NewThinger("boid")
-			roboid.SetX(300)
-			roboid.SetY(200)
-			rc := NewBoidController(1)
-			rc.settles = true
-			if pl, ok := ctx.Referables.ByFirstTag("qi").(*Thinger); ok {
-				rc.targetID = pl.ID()
-			}
-			roboid.controller = rc
-			roboid.centerX = 0.5
-			roboid.centerY = 0.5
-			roboid.SetPriority(ables.PriorityMiddle)
-			roboid.SetTag("boid")
-			roboid.Stack("roboid")
-			ctx.Place.referables = append(ctx.Place.referables, roboid)
-			for i := 0; i < 20; i++ {
-				b := NewThinger("boid")
-				bc := NewBoidController(1)
-				bc.settles = true
-				bc.targetID = roboid.ID()
-				b.controller = bc
-				b.centerX = 0.5
-				b.centerY = 0.5
-				b.SetPriority(ables.PriorityMiddle)
-				b.SetTag("boid")
-				if i%2 == 0 {
-					b.Stack("boid2")
-				}
-				ctx.Place.referables = append(ctx.Place.referables, b)
-			}
-		}
-
-	}
-	ctx.Place.referables = append(ctx.Place.referables, NewFadeInOverlay(int(ctx.Width), int(ctx.Height), 50))
+	ctx.Place = ctx.GetPlace(placeName)
 	// Move player into position.
 	if enter != "" {
 		if area := ctx.Place.GetAreaByFirstTag(enter); area != nil {
@@ -100,6 +85,7 @@ func (c *ChangeTravel) Apply(ctx *ContextGame) {
 			}
 		}
 	}
+	ctx.Camera.Snap()
 }
 
 type ChangeState struct {
@@ -112,7 +98,7 @@ func (c *ChangeState) Apply(ctx *ContextGame) {
 	}
 }
 
-// ChangeAcquireItem finds the given area with the tag, deletes the area, and adds the item as an inventory to the player using Tag for its identifier and the area's Message for its name.
+// ChangeAcquireItem finds the given area with the tag, deletes the area, and adds the item as an inventory to the player using Tag as its item's ID.
 type ChangeAcquireItem struct {
 	Tag string
 }
@@ -122,11 +108,11 @@ func (c *ChangeAcquireItem) Apply(ctx *ContextGame) {
 	// Just get our player.
 	if pl, ok := ctx.Referables.ByFirstTag("qi").(*Thinger); ok {
 		if area := ctx.Place.GetAreaByFirstTag(c.Tag); area != nil {
-			pl.AddItem(area.original.Text, area.original.Tag)
+			pl.AddItem(c.Tag, res.GetItem(c.Tag).Stackable)
 			// Delete the area...
 			ctx.Place.RemoveAreaByFirstTag(c.Tag)
 			// Also delete any associated referable in the map.
-			ctx.Place.referables.RemoveByFirstTag(c.Tag)
+			ctx.Place.RemoveReferableByFirstTag(c.Tag)
 		}
 	}
 }
@@ -143,6 +129,28 @@ func (c *ChangeLoseItem) Apply(ctx *ContextGame) {
 	}
 }
 
+// ChangeCombineItems puts two of the player's items together, replacing them with whatever they make.
+type ChangeCombineItems struct {
+	Tag  string
+	With string
+}
+
+// Apply applies the change to the game.
+func (c *ChangeCombineItems) Apply(ctx *ContextGame) {
+	made, ok := res.Combine(c.Tag, c.With)
+	if !ok {
+		return
+	}
+	if pl, ok := ctx.Referables.ByFirstTag("qi").(*Thinger); ok {
+		if !pl.HasItem(c.Tag) || !pl.HasItem(c.With) {
+			return
+		}
+		pl.RemoveItem(c.Tag)
+		pl.RemoveItem(c.With)
+		pl.AddItem(made, res.GetItem(made).Stackable)
+	}
+}
+
 type ChangeUse struct {
 	Tag string
 }
@@ -150,40 +158,63 @@ type ChangeUse struct {
 func (c *ChangeUse) Apply(ctx *ContextGame) {
 	// Alright, let's see what the given area does.
 	if area := ctx.Place.GetAreaByFirstTag(c.Tag); area != nil {
-		targets := strings.Split(area.original.TargetTag, ";")
-		actions := strings.Split(area.original.TargetAction, ";")
-		for i, target := range targets {
-			var act string
-			if i < len(actions) {
-				act = actions[i]
-			} else if len(actions) > 0 {
-				act = actions[0]
+		useTargets(ctx, strings.Split(area.original.TargetTag, ";"), strings.Split(area.original.TargetAction, ";"))
+	}
+}
+
+// ChangeContainerFilled does a container's actions, as its TargetItem was put in it.
+type ChangeContainerFilled struct {
+	Tag string
+}
+
+// Apply applies the change to the game.
+func (c *ChangeContainerFilled) Apply(ctx *ContextGame) {
+	if container := ctx.Place.GetContainer(c.Tag); container != nil {
+		useTargets(ctx, strings.Split(container.original.TargetTag, ";"), strings.Split(container.original.TargetAction, ";"))
+	}
+}
+
+// useTargets does each action to the areas, referables, and containers with the matching target tag. If there are fewer actions than targets, the first action is used for the rest.
+func useTargets(ctx *ContextGame, targets, actions []string) {
+	for i, target := range targets {
+		var act string
+		if i < len(actions) {
+			act = actions[i]
+		} else if len(actions) > 0 {
+			act = actions[0]
+		}
+		if area2 := ctx.Place.GetAreaByFirstTag(target); area2 != nil {
+			if act == "del" {
+				ctx.Place.RemoveAreaByFirstTag(target)
+			} else if act == "enable" {
+				area2.original.Disabled = false
+			} else if act == "disable" {
+				area2.original.Disabled = true
 			}
-			if area2 := ctx.Place.GetAreaByFirstTag(target); area2 != nil {
-				if act == "del" {
-					ctx.Place.RemoveAreaByFirstTag(target)
-				} else if act == "enable" {
-					area2.original.Disabled = false
-				} else if act == "disable" {
-					area2.original.Disabled = true
+		}
+		// Check referables too, I guess.
+		if ref := ctx.Place.referables.ByFirstTag(target); ref != nil {
+			if act == "del" {
+				ctx.Place.RemoveReferableByFirstTag(target)
+			} else if e, ok := ref.(*Emitter); ok && act == "burst" {
+				e.Burst()
+			} else if e, ok := ref.(*Emitter); ok && (act == "enable" || act == "disable") {
+				e.SetEnabled(act == "enable")
+			} else if strings.HasPrefix(act, "anim") {
+				parts := strings.Split(act, ":")
+				if len(parts) < 2 {
+					continue
 				}
-			}
-			// Check referables too, I guess.
-			if ref := ctx.Place.referables.ByFirstTag(target); ref != nil {
-				if act == "del" {
-					ctx.Place.referables.RemoveByFirstTag(target)
-				} else if strings.HasPrefix(act, "anim") {
-					parts := strings.Split(act, ":")
-					if len(parts) < 2 {
-						continue
-					}
-					ctx.Place.referables.ByFirstTag(target)
-					if s, ok := ref.(*Staticer); ok {
-						s.Animation(parts[1])
-					}
+				ctx.Place.referables.ByFirstTag(target)
+				if s, ok := ref.(*Staticer); ok {
+					s.Animation(parts[1])
 				}
 			}
 		}
+		// And containers, which get opened for the player to rummage through.
+		if container := ctx.Place.GetContainer(target); container != nil && act == "open" {
+			ctx.Opened = container
+		}
 	}
 }
 
@@ -215,3 +246,19 @@ type ChangeRemoveReferable struct {
 func (c *ChangeRemoveReferable) Apply(ctx *ContextGame) {
 	ctx.Referables.RemoveByID(c.ID)
 }
+
+// ChangeSound plays a sound effect, from a point in za warudo if it is positional.
+type ChangeSound struct {
+	Name       string
+	X, Y       float64
+	Positional bool
+}
+
+// Apply plays the sound.
+func (c *ChangeSound) Apply(ctx *ContextGame) {
+	if c.Positional {
+		audio.PlayAt(c.Name, c.X, c.Y)
+	} else {
+		audio.Play(c.Name)
+	}
+}
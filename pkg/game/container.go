@@ -0,0 +1,64 @@
+package game
+
+import (
+	"slices"
+
+	"github.com/kettek/ehh24/pkg/game/ables"
+	"github.com/kettek/ehh24/pkg/res"
+)
+
+// Container is a place's locker, table, or whatever else holds items, with what it holds.
+type Container struct {
+	original *res.Container
+	ables.Storagable
+	triggered bool // TargetItem is in it and its actions were done.
+}
+
+// newContainer makes a container holding its starting items.
+func newContainer(original *res.Container) *Container {
+	c := &Container{original: original}
+	for _, id := range original.StartingItems() {
+		c.AddItem(id, res.GetItem(id).Stackable)
+	}
+	return c
+}
+
+// Tag returns the container's tag.
+func (c *Container) Tag() string {
+	return c.original.Tag
+}
+
+// Kind returns what kind of container it is.
+func (c *Container) Kind() res.ContainerKind {
+	return c.original.Kind
+}
+
+// Accepts returns if the item can be put in the container.
+func (c *Container) Accepts(tag string) bool {
+	if accepted := c.original.AcceptedItems(); accepted != nil && !slices.Contains(accepted, tag) {
+		return false
+	}
+	if c.original.Capacity <= 0 || len(c.Storagable) < c.original.Capacity {
+		return true
+	}
+	// A full container can still take more of something it stacks.
+	return res.GetItem(tag).Stackable && c.HasItem(tag)
+}
+
+// Update asks for the container's actions to be done when its TargetItem is put in.
+func (c *Container) Update(ctx *ContextGame) (changes []Change) {
+	if c.original.TargetItem == "" {
+		return nil
+	}
+	has := c.HasItem(c.original.TargetItem)
+	if has && !c.triggered {
+		changes = append(changes, &ChangeContainerFilled{Tag: c.Tag()}, &ChangeSound{Name: "use"})
+	}
+	c.triggered = has
+	return changes
+}
+
+// settle marks the container as triggered if its TargetItem is already in it, such as from a save, without doing its actions again.
+func (c *Container) settle() {
+	c.triggered = c.original.TargetItem != "" && c.HasItem(c.original.TargetItem)
+}
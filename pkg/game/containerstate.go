@@ -0,0 +1,112 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/ehh24/pkg/audio"
+	"github.com/kettek/ehh24/pkg/game/ables"
+	"github.com/kettek/ehh24/pkg/game/context"
+	"github.com/kettek/ehh24/pkg/menu"
+	"github.com/kettek/ehh24/pkg/res"
+	"github.com/kettek/ehh24/pkg/statemachine"
+)
+
+// ContainerState is a screen over the frozen game for moving items between the player and an opened container. What is in the container is listed first, to be taken, then what the player has, to be put in.
+type ContainerState struct {
+	menu      *menu.Menu
+	container *Container
+	storage   *ables.Storagable // The player's.
+	tags      []string          // Item on each line of the menu, if any.
+	staxers   map[string]Staxer
+	rotation  float64
+	dctx      context.Draw
+}
+
+// NewContainerState makes a screen for the container and the player's storage.
+func NewContainerState(container *Container, storage *ables.Storagable) *ContainerState {
+	s := &ContainerState{
+		container: container,
+		storage:   storage,
+		staxers:   make(map[string]Staxer),
+	}
+	s.menu = menu.New(container.Kind().String())
+	s.menu.Back = func() statemachine.State {
+		return statemachine.Pop(nil)
+	}
+	s.refresh()
+	return s
+}
+
+// Init is called when the state is to be first entered.
+func (s *ContainerState) Init() {
+	ebiten.SetCursorMode(ebiten.CursorModeVisible)
+}
+
+// refresh rebuilds the menu from what is where.
+func (s *ContainerState) refresh() {
+	s.menu.Items = nil
+	s.tags = nil
+	add := func(label, tag string, activate func() statemachine.State) {
+		s.menu.Items = append(s.menu.Items, menu.Item{Label: label, Activate: activate})
+		s.tags = append(s.tags, tag)
+		if _, ok := s.staxers[tag]; !ok && tag != "" {
+			s.staxers[tag] = NewStaxer(res.GetItem(tag).Stax)
+		}
+	}
+
+	for _, item := range s.container.Storagable {
+		add(fmt.Sprintf("Take %s x%d", res.GetText(res.GetItem(item.Tag).Name), item.Count), item.Tag, func() statemachine.State {
+			s.move(&s.container.Storagable, s.storage, item.Tag)
+			return nil
+		})
+	}
+	if len(s.container.Storagable) == 0 {
+		add("Empty", "", nil)
+	}
+	for _, item := range *s.storage {
+		label := fmt.Sprintf("Put %s x%d", res.GetText(res.GetItem(item.Tag).Name), item.Count)
+		if !s.container.Accepts(item.Tag) {
+			add(label+" -", item.Tag, nil)
+			continue
+		}
+		add(label, item.Tag, func() statemachine.State {
+			s.move(s.storage, &s.container.Storagable, item.Tag)
+			return nil
+		})
+	}
+	add("Back", "", s.menu.Back)
+	s.menu.Selected = min(s.menu.Selected, len(s.menu.Items)-1)
+}
+
+// move moves one of the item from one storage to the other.
+func (s *ContainerState) move(from, to *ables.Storagable, tag string) {
+	from.RemoveItem(tag)
+	to.AddItem(tag, res.GetItem(tag).Stackable)
+	audio.Play("pickup")
+	s.refresh()
+}
+
+// Update updates the menu and spins the selected item around.
+func (s *ContainerState) Update() statemachine.State {
+	s.rotation += 0.02
+	return s.menu.Update()
+}
+
+// Draw draws the list, with the selected item and what it is off to the side of it.
+func (s *ContainerState) Draw(screen *ebiten.Image) {
+	s.menu.Draw(screen)
+	if s.menu.Selected < 0 || s.menu.Selected >= len(s.tags) || s.tags[s.menu.Selected] == "" {
+		return
+	}
+	tag := s.tags[s.menu.Selected]
+	drawItemPreview(&s.dctx, screen, s.staxers[tag], s.rotation, res.GetText(res.GetItem(tag).Description))
+}
+
+// Layout does a layout.
+func (s *ContainerState) Layout(ow, oh int) (int, int) {
+	return ow, oh
+}
+
+// Overlay is drawn over the game.
+func (s *ContainerState) Overlay() {}
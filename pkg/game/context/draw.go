@@ -5,7 +5,10 @@ import (
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/kettek/ehh24/pkg/lang"
+	"github.com/kettek/ehh24/pkg/render"
 	"github.com/kettek/ehh24/pkg/res"
+	"github.com/kettek/ehh24/pkg/statemachine"
 )
 
 type Draw struct {
@@ -13,11 +16,22 @@ type Draw struct {
 	Height float64
 	Target *ebiten.Image
 	Op     *ebiten.DrawImageOptions
+	Batch  render.Batch // Stacked sprites waiting to be drawn to Target.
 	//GeoM   ebiten.GeoM
 }
 
+// DrawSprite queues a stacked sprite to be drawn with the context's GeoM and blend.
+func (d *Draw) DrawSprite(s *render.Sprite) {
+	d.Batch.Add(d.Target, s, d.Op.GeoM, d.Op.Blend)
+}
+
+// Flush draws any queued sprites. Anything drawing straight to Target must call this first.
+func (d *Draw) Flush() {
+	d.Batch.Flush()
+}
+
 func (d *Draw) MousePosition() (float64, float64) {
-	x, y := ebiten.CursorPosition()
+	x, y := statemachine.CursorPosition()
 
 	if x < 0 {
 		x = 0
@@ -30,10 +44,10 @@ func (d *Draw) MousePosition() (float64, float64) {
 		y = int(d.Height)
 	}
 
-	scaleX := d.Op.GeoM.Element(0, 0)
-	scaleY := d.Op.GeoM.Element(1, 1)
+	geom := d.Op.GeoM
+	geom.Invert()
 
-	return float64(x) / scaleX, float64(y) / scaleY
+	return geom.Apply(float64(x), float64(y))
 }
 
 func (d *Draw) Size() (float64, float64) {
@@ -43,7 +57,11 @@ func (d *Draw) Size() (float64, float64) {
 	return d.Width / scaleX, d.Height / scaleY
 }
 
+// Text draws text centered on the geom's position, written in the current language.
 func (d *Draw) Text(t string, geom ebiten.GeoM, c color.Color) {
+	d.Flush()
+	t = lang.Text(t)
+
 	op := &text.DrawOptions{}
 	//op.PrimaryAlign = text.AlignCenter // Ugh rendering from center with pixel fonts turns it fuzzy...
 	op.GeoM.Concat(geom)
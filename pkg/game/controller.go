@@ -1,6 +1,7 @@
 package game
 
 import (
+	"github.com/kettek/ehh24/pkg/config"
 	"github.com/kettek/ehh24/pkg/res"
 	input "github.com/quasilyte/ebitengine-input"
 )
@@ -21,37 +22,43 @@ type PlayerController struct {
 	lastMouseX      float64
 	lastMouseY      float64
 	impatience      float64
-	heldItem        *InvItem // this is dangerussy
+	heldItem        string // Tag of the item in hand, if any.
+	dragging        bool   // The held item is being dragged, so letting go uses it, rather than it having been clicked up.
 }
 
-// Our inputs for moving with a PlayerController.
+// Our inputs for moving with a PlayerController. Pointing at things is left to the Pointer.
 const (
 	InputLeft input.Action = iota
 	InputRight
 	InputUp
 	InputDown
-	InputMoveTo
 )
 
+// playerBindings are the config bindings of our inputs.
+var playerBindings = map[input.Action]string{
+	InputLeft:  "Left",
+	InputRight: "Right",
+	InputUp:    "Up",
+	InputDown:  "Down",
+}
+
 // NewPlayerController creates a new PlayerController.
 func NewPlayerController(insys *input.System) *PlayerController {
-	keymap := input.Keymap{
-		InputLeft:   {input.KeyGamepadLStickLeft, input.KeyLeft, input.KeyA},
-		InputRight:  {input.KeyGamepadLStickRight, input.KeyRight, input.KeyD},
-		InputUp:     {input.KeyGamepadLStickUp, input.KeyUp, input.KeyW},
-		InputDown:   {input.KeyGamepadLStickDown, input.KeyDown, input.KeyS},
-		InputMoveTo: {input.KeyMouseLeft},
-	}
 	pc := &PlayerController{
-		input: insys.NewHandler(0, keymap),
+		input: insys.NewHandler(0, config.Keymap(playerBindings)),
 	}
 
 	return pc
 }
 
+// Remap rebinds the controller's inputs to the config's bindings.
+func (p *PlayerController) Remap() {
+	p.input.Remap(config.Keymap(playerBindings))
+}
+
 // Update updates the PlayerController.
 func (p *PlayerController) Update(ctx *ContextGame, t *Thinger) (a []Action) {
-	if p.block {
+	if p.block || ctx.InputBlocked() {
 		return a
 	}
 	// First see if thinger has hit a trigger area.
@@ -62,11 +69,13 @@ func (p *PlayerController) Update(ctx *ContextGame, t *Thinger) (a []Action) {
 				case res.PolygonTriggerTravel:
 					if area.original.TargetTag != "" {
 						a = append(a, &ActionTravel{
-							Place: area.original.TargetTag,
+							Place:      area.original.TargetTag,
+							Transition: area.original.Transition,
 						})
 						p.action = nil
 						p.monologueAction = nil
-						p.heldItem = nil
+						p.heldItem = ""
+						p.dragging = false
 						return
 					}
 				case res.PolygonTriggerState:
@@ -83,7 +92,7 @@ func (p *PlayerController) Update(ctx *ContextGame, t *Thinger) (a []Action) {
 
 	// Otherwise handle click actions.
 	x, y := ctx.MousePosition()
-	w, h := ctx.Size()
+	w, h := ctx.Camera.ViewSize(ctx)
 
 	// Look in a direction if we're not doing a move action.
 	if x != p.lastMouseX || y != p.lastMouseY {
@@ -141,21 +150,21 @@ func (p *PlayerController) Update(ctx *ContextGame, t *Thinger) (a []Action) {
 				}
 			}
 		}
-		if p.input.ActionIsJustPressed(InputMoveTo) {
+		if ctx.Pointer.Confirmed() || (p.dragging && ctx.Pointer.Dropped()) {
 			if hitArea != nil {
 				cx, _ := hitArea.Center()
 				_, _, _, my := hitArea.Bounds()
 				if hitArea.original.SubKind == res.PolygonInteractUse {
 					if hitArea.original.TargetItem != "" {
-						if p.heldItem == nil {
+						if p.heldItem == "" {
 							p.monologueAction = &ActionMonologue{
 								Text:  "ダメ",
 								Timer: 100,
 							}
-						} else if p.heldItem.item.Tag == hitArea.original.TargetItem {
+						} else if p.heldItem == hitArea.original.TargetItem {
 							p.action = &ActionUse{
 								Target: hitArea.original.Tag,
-								Item:   p.heldItem.item.Tag, // Remove from inventory
+								Item:   p.heldItem, // Remove from inventory
 								ActionMoveTo: ActionMoveTo{
 									X:     cx,
 									Y:     my + 5,
@@ -233,7 +242,29 @@ func (p *PlayerController) Update(ctx *ContextGame, t *Thinger) (a []Action) {
 				}
 			}
 			// Ehh...
-			p.heldItem = nil
+			p.heldItem = ""
+			p.dragging = false
+		} else if ctx.Pointer.Looked() {
+			// Say what it is without going over to it.
+			if hitArea != nil && hitArea.original.Text != "" {
+				p.monologueAction = &ActionMonologue{
+					Text:  hitArea.original.Text,
+					Timer: 100,
+				}
+			} else if hitSelf {
+				p.monologueAction = &ActionMonologue{
+					Text:  "チ",
+					Timer: 100,
+				}
+			}
+		} else if ctx.Pointer.Cancelled() {
+			// Put back whatever we're holding, otherwise stop whatever we're doing.
+			if p.heldItem != "" {
+				p.heldItem = ""
+				p.dragging = false
+			} else {
+				p.action = nil
+			}
 		}
 	}
 
@@ -326,7 +357,7 @@ func NewCursorController() *CursorController {
 
 // Update creates ActionPosition for adjusting the cursor's position.
 func (c *CursorController) Update(ctx *ContextGame, t *Thinger) (a []Action) {
-	x, y := ctx.MousePosition()
+	x, y := ctx.UIMousePosition()
 
 	a = append(a, &ActionPosition{
 		X: x,
@@ -0,0 +1,10 @@
+//go:build debug
+
+package game
+
+import "fmt"
+
+// debugLog prints the values, as fmt.Println does.
+func debugLog(a ...any) {
+	fmt.Println(a...)
+}
@@ -1,39 +1,208 @@
 package game
 
 import (
+	"fmt"
+	"image/color"
+	"math"
 	"math/rand/v2"
 
+	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/kettek/ehh24/pkg/game/ables"
 	"github.com/kettek/ehh24/pkg/game/context"
+	"github.com/kettek/ehh24/pkg/render"
+	"github.com/kettek/ehh24/pkg/res"
+	"github.com/kettek/ehh24/pkg/stax"
 )
 
+// pixel is what particles without a sprite are drawn with.
+var pixel *ebiten.Image
+
 // Emitter sprays stuff.
 type Emitter struct {
 	ables.Positionable
 	ables.IDable
 	ables.Tagable
 	ables.Priorityable
-	particles []Particle
+	original  *res.Emitter
+	particles []Particle // Pool of particles, with the living ones first.
+	alive     int
+	pending   float64 // Fraction of a particle owed from Rate.
+	enabled   bool
+	sprite    render.Sprite
+	animation *stax.Animation // Animation particles play through over their life, if the sprite is a stax.
 }
 
+// Particle is a single bit of spray.
 type Particle struct {
-	ables.Positionable
+	x, y     float64
+	vx, vy   float64
+	age      int
 	lifetime int
 }
 
-// NewEmitter makes a staticer, wow.
-func NewEmitter(name string) *Emitter {
-	return &Emitter{
-		Positionable: ables.MakePositionable(32+rand.Float64()*256, 32+rand.Float64()*256),
+// NewEmitter makes an emitter from a place's emitter, wow.
+func NewEmitter(e *res.Emitter) *Emitter {
+	t := &Emitter{
+		Positionable: ables.MakePositionable(float64(e.Point.X), float64(e.Point.Y)),
+		IDable:       ables.NextIDable(),
+		Tagable:      ables.MakeTagable(e.Tag),
+		original:     e,
+		particles:    make([]Particle, max(e.Max, e.Burst, 1)),
+		enabled:      !e.Disabled,
+	}
+	t.setSprite(e.Sprite)
+	return t
+}
+
+// setSprite sets what particles look like, falling back to a pixel if the name is neither a stax nor an image.
+func (t *Emitter) setSprite(name string) {
+	t.animation = nil
+	if st, err := res.GetStax(name); err == nil && len(st.Stax.Stacks) > 0 {
+		stack := &st.Stax.Stacks[0]
+		if s := st.Stax.Stack(t.original.Stack); s != nil {
+			stack = s
+		}
+		if len(stack.Animations) > 0 {
+			t.animation = &stack.Animations[0]
+			if a := stack.Animation(t.original.Animation); a != nil {
+				t.animation = a
+			}
+			t.sprite = render.Sprite{
+				Image:         st.EbiImage,
+				SliceWidth:    st.Stax.SliceWidth,
+				SliceHeight:   st.Stax.SliceHeight,
+				Frame:         t.animation.Frame(0),
+				OriginX:       -0.5,
+				OriginY:       -0.5,
+				SliceDistance: 1,
+			}
+			return
+		}
+	}
+
+	img, ok := res.Images[name]
+	if !ok {
+		if pixel == nil {
+			pixel = ebiten.NewImage(1, 1)
+			pixel.Fill(color.White)
+		}
+		img = pixel
+	}
+	// A lone image is just a stax with one slice.
+	b := img.Bounds()
+	t.sprite = render.Sprite{
+		Image:       img,
+		SliceWidth:  b.Dx(),
+		SliceHeight: b.Dy(),
+		Frame:       &stax.Frame{Slices: []stax.Slice{{X: b.Min.X, Y: b.Min.Y}}},
+		OriginX:     -0.5,
+		OriginY:     -0.5,
 	}
 }
 
-// Update updates the particles
+// Enabled returns if the emitter is emitting at its rate.
+func (t *Emitter) Enabled() bool {
+	return t.enabled
+}
+
+// SetEnabled starts or stops emitting at the emitter's rate. Particles already out keep going.
+func (t *Emitter) SetEnabled(enabled bool) {
+	t.enabled = enabled
+}
+
+// Burst emits the emitter's burst of particles all at once.
+func (t *Emitter) Burst() {
+	t.Emit(t.original.Burst)
+}
+
+// Emit emits count particles, or as many as there is room for in the pool.
+func (t *Emitter) Emit(count int) {
+	e := t.original
+	for i := 0; i < count && t.alive < len(t.particles); i++ {
+		angle := e.Angle + (rand.Float64()-0.5)*e.Spread
+		speed := e.Speed + rand.Float64()*e.SpeedSpread
+		lifetime := e.Lifetime
+		if e.LifetimeSpread > 0 {
+			lifetime += rand.IntN(e.LifetimeSpread + 1)
+		}
+		t.particles[t.alive] = Particle{
+			x:        t.X(),
+			y:        t.Y(),
+			vx:       math.Cos(angle) * speed,
+			vy:       math.Sin(angle) * speed,
+			lifetime: max(lifetime, 1),
+		}
+		t.alive++
+	}
+}
+
+// Update emits at the emitter's rate and moves the particles along, returning dead ones to the pool.
 func (t *Emitter) Update(ctx *ContextGame) []Change {
+	e := t.original
+
+	if t.enabled && e.Rate > 0 {
+		t.pending += e.Rate
+		count := int(t.pending)
+		t.pending -= float64(count)
+		t.Emit(count)
+	}
+
+	for i := 0; i < t.alive; {
+		p := &t.particles[i]
+		p.age++
+		if p.age >= p.lifetime {
+			// Swap the last living particle in, so the living stay packed at the front.
+			t.alive--
+			t.particles[i] = t.particles[t.alive]
+			continue
+		}
+		p.vy += e.Gravity
+		p.vx *= 1 - e.Drag
+		p.vy *= 1 - e.Drag
+		p.x += p.vx
+		p.y += p.vy
+		i++
+	}
 	return nil
 }
 
 // Draw draws the emitter bits.
 func (t *Emitter) Draw(ctx *context.Draw) {
-	// TODODO
+	e := t.original
+	sprite := t.sprite
+	for _, p := range t.particles[:t.alive] {
+		life := float64(p.age) / float64(p.lifetime)
+
+		if t.animation != nil && len(t.animation.Frames) > 0 {
+			sprite.Frame = t.animation.Frame(int(life * float64(len(t.animation.Frames))))
+		}
+		sprite.X = p.x
+		sprite.Y = p.y
+
+		clr := lerpColor(e.StartColor, e.EndColor, life)
+		sprite.ColorScale.Reset()
+		sprite.ColorScale.ScaleWithColor(clr)
+
+		// Scale around the particle itself.
+		scale := e.StartScale + (e.EndScale-e.StartScale)*life
+		var geom ebiten.GeoM
+		geom.Translate(-p.x, -p.y)
+		geom.Scale(scale, scale)
+		geom.Translate(p.x, p.y)
+		geom.Concat(ctx.Op.GeoM)
+
+		ctx.Batch.Add(ctx.Target, &sprite, geom, ctx.Op.Blend)
+	}
+}
+
+// lerpColor blends from a to b by f.
+func lerpColor(a, b color.NRGBA, f float64) color.NRGBA {
+	lerp := func(a, b uint8) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*f)
+	}
+	return color.NRGBA{lerp(a.R, b.R), lerp(a.G, b.G), lerp(a.B, b.B), lerp(a.A, b.A)}
+}
+
+func (t *Emitter) String() string {
+	return fmt.Sprintf("%d:%s:%d:%d", t.ID(), t.Tag(), t.Priority(), t.alive)
 }
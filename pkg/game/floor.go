@@ -2,11 +2,8 @@ package game
 
 import (
 	"fmt"
-	"image"
-	"math"
 	"math/rand/v2"
 
-	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/kettek/ehh24/pkg/game/ables"
 	"github.com/kettek/ehh24/pkg/game/context"
 )
@@ -36,31 +33,13 @@ func NewFloor(name string) *Floor {
 
 // Draw draws the staticer to da screen.
 func (t *Floor) Draw(ctx *context.Draw) {
-	scale := ctx.Op.GeoM.Element(0, 0)
-
-	const sliceDistance = 1.5
-	sliceDistanceEnd := math.Max(1, sliceDistance*scale)
-
-	opts := &ebiten.DrawImageOptions{}
-	for i, slice := range t.frame.Slices {
-		for j := 0; j < int(sliceDistanceEnd); j++ {
-			opts.GeoM.Reset()
-			//opts.GeoM.Rotate(math.Pi / 30)
-			opts.GeoM.Translate(float64(t.stax.Stax.SliceWidth)*t.originX, float64(t.stax.Stax.SliceHeight)*t.originY)
-
-			opts.GeoM.Translate(t.X(), t.Y())
-
-			opts.GeoM.Translate(0, -sliceDistance*float64(i))
-
-			opts.GeoM.Concat(ctx.Op.GeoM)
-
-			opts.GeoM.Translate(0, float64(j))
-
-			opts.Blend = ctx.Op.Blend
-			sub := t.stax.EbiImage.SubImage(image.Rect(slice.X, slice.Y, slice.X+t.stax.Stax.SliceWidth, slice.Y+t.stax.Stax.SliceHeight)).(*ebiten.Image)
-			ctx.Target.DrawImage(sub, opts)
-		}
-	}
+	sprite := t.sprite()
+	sprite.X = t.X()
+	sprite.Y = t.Y()
+	sprite.OriginX = t.originX
+	sprite.OriginY = t.originY
+	sprite.SliceDistance = 1.5
+	ctx.DrawSprite(&sprite)
 }
 
 func (t *Floor) String() string {
@@ -1,36 +1,93 @@
 package game
 
-import "github.com/hajimehoshi/ebiten/v2"
+import (
+	"github.com/kettek/ehh24/pkg/game/ables"
+	"github.com/kettek/ehh24/pkg/statemachine"
+	"github.com/kettek/ehh24/pkg/transition"
+)
 
 // ContextGame is the context of the game, wow.
 type ContextGame struct {
 	Width      float64
 	Height     float64
-	Zoom       float64
+	Zoom       float64 // Base zoom of the UI. The camera zooms the world on top of this.
+	Camera     *Camera
+	Lighting   *Lighting
 	Referables Referables
 	Places     map[string]*Place
 	Place      *Place
+	Pointer    *Pointer
+	Transition *transition.Transition // Place transition in progress, if any.
+	Opened     *Container             // Container just opened, to be shown once changes are done.
+}
+
+// InputBlocked returns if input should be ignored, as the screen is transitioning to somewhere else.
+func (c *ContextGame) InputBlocked() bool {
+	return c.Transition != nil || statemachine.Transitioning()
 }
 
 // MousePosition returns the position of the mouse in world coordinates.
 func (c *ContextGame) MousePosition() (float64, float64) {
-	x, y := ebiten.CursorPosition()
+	x, y := c.screenMousePosition()
+	return c.Camera.ScreenToWorld(c, x, y)
+}
 
-	if x < 0 {
-		x = 0
-	} else if x > int(c.Width) {
-		x = int(c.Width)
-	}
-	if y < 0 {
-		y = 0
-	} else if y > int(c.Height) {
-		y = int(c.Height)
-	}
+// UIMousePosition returns the position of the mouse in UI coordinates.
+func (c *ContextGame) UIMousePosition() (float64, float64) {
+	x, y := c.screenMousePosition()
+	return x / c.Zoom, y / c.Zoom
+}
 
-	return float64(x) / c.Zoom, float64(y) / c.Zoom
+// screenMousePosition returns the position of the pointer on the screen, which is the mouse unless a gamepad moved it.
+func (c *ContextGame) screenMousePosition() (float64, float64) {
+	return c.Pointer.X, c.Pointer.Y
 }
 
-// Size returns the size of the view accounting for zoom.
+// Size returns the size of the UI view accounting for zoom.
 func (c *ContextGame) Size() (float64, float64) {
 	return c.Width / c.Zoom, c.Height / c.Zoom
 }
+
+// GetPlace returns the place with the given name, loading it in if it hasn't been visited yet.
+func (c *ContextGame) GetPlace(name string) *Place {
+	if place, ok := c.Places[name]; ok {
+		return place
+	}
+	place := NewPlace(name)
+	c.Places[name] = place
+
+	// Alright, hacky time...
+	if name == "outside" {
+		roboid := NewThinger("boid")
+		roboid.SetX(300)
+		roboid.SetY(200)
+		rc := NewBoidController(1)
+		rc.settles = true
+		if pl, ok := c.Referables.ByFirstTag("qi").(*Thinger); ok {
+			rc.targetID = pl.ID()
+		}
+		roboid.controller = rc
+		roboid.centerX = 0.5
+		roboid.centerY = 0.5
+		roboid.SetPriority(ables.PriorityMiddle)
+		roboid.SetTag("boid")
+		roboid.Stack("roboid")
+		place.referables = append(place.referables, roboid)
+		for i := 0; i < 20; i++ {
+			b := NewThinger("boid")
+			bc := NewBoidController(1)
+			bc.settles = true
+			bc.targetID = roboid.ID()
+			b.controller = bc
+			b.centerX = 0.5
+			b.centerY = 0.5
+			b.SetPriority(ables.PriorityMiddle)
+			b.SetTag("boid")
+			if i%2 == 0 {
+				b.Stack("boid2")
+			}
+			place.referables = append(place.referables, b)
+		}
+	}
+	return place
+}
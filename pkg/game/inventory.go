@@ -1,30 +1,54 @@
 package game
 
 import (
-	"image"
+	"fmt"
 	"image/color"
 	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/ehh24/pkg/config"
 	"github.com/kettek/ehh24/pkg/game/ables"
 	"github.com/kettek/ehh24/pkg/game/context"
 	"github.com/kettek/ehh24/pkg/res"
+	input "github.com/quasilyte/ebitengine-input"
 )
 
+// Our inputs for picking items in the inventory with keys.
+const (
+	InputPrevItem input.Action = iota
+	InputNextItem
+)
+
+// inventoryBindings are the config bindings of our inputs.
+var inventoryBindings = map[input.Action]string{
+	InputPrevItem: "PrevItem",
+	InputNextItem: "NextItem",
+}
+
+// inventorySlots is how many items fit around the orb at once. Any more go on further pages.
+const inventorySlots = 6
+
 type Inventory struct {
 	ables.IDable
 	ables.Priorityable
 	ables.Tagable
 	ables.Positionable
-	tw            float64
-	th            float64
-	width         float64
-	height        float64
-	targetTag     string // eh, whatever
-	items         []InvItem
-	hoveredName   string
-	fade          int
-	heldItemIndex int
+	tw        float64
+	th        float64
+	width     float64
+	height    float64
+	targetTag string // eh, whatever
+	items     []InvItem
+	input     *input.Handler
+	page      int
+	selected  int // Index of the item last hovered or picked with keys.
+	hovered   int // Index of the item under the pointer, or -1.
+	examined  string
+	fade      int
+	pointerX  float64 // Where the pointer is, in UI pixels.
+	pointerY  float64
+	held      InvItem // What the player has in hand, to be drawn at the pointer.
+	dragging  bool
 }
 
 const fadeMax = 40
@@ -35,10 +59,12 @@ type InvItem struct {
 	staxer Staxer
 }
 
-func NewInventory(tag string) *Inventory {
+func NewInventory(insys *input.System, tag string) *Inventory {
 	inv := &Inventory{
 		targetTag: tag,
 		fade:      fadeMin,
+		hovered:   -1,
+		input:     insys.NewHandler(0, config.Keymap(inventoryBindings)),
 	}
 	inv.SetPriority(ables.PriorityUI)
 	inv.SetTag("inventory")
@@ -49,11 +75,17 @@ func NewInventory(tag string) *Inventory {
 	return inv
 }
 
+// Remap rebinds the inventory's inputs to the config's bindings.
+func (inv *Inventory) Remap() {
+	inv.input.Remap(config.Keymap(inventoryBindings))
+}
+
+// ItemBounds returns the bounds of the slot the item at index sits in, on whichever page it is on.
 func (inv *Inventory) ItemBounds(index int) (x, y, x2, y2 float64) {
 	cx := inv.X() + inv.width/2
 	cy := inv.Y() + inv.height/2
 
-	ratio := float64(index) / 6
+	ratio := float64(index%inventorySlots) / inventorySlots
 	angle := ratio*math.Pi*2 + math.Pi
 	x = cx + math.Cos(angle)*16
 	y = cy + math.Sin(angle)*16
@@ -64,6 +96,16 @@ func (inv *Inventory) ItemBounds(index int) (x, y, x2, y2 float64) {
 	return x, y, x + 9, y + 9
 }
 
+// pages returns how many pages the items take up.
+func (inv *Inventory) pages() int {
+	return max(1, (len(inv.items)+inventorySlots-1)/inventorySlots)
+}
+
+// onPage returns if the item at index is on the current page.
+func (inv *Inventory) onPage(index int) bool {
+	return index/inventorySlots == inv.page
+}
+
 func (inv *Inventory) SyncTo(storage ables.Storagable) {
 	// Grow/shrink.
 	if len(inv.items) < len(storage) {
@@ -76,10 +118,56 @@ func (inv *Inventory) SyncTo(storage ables.Storagable) {
 	// Sync items.
 	for i, item := range storage {
 		if item.Tag != inv.items[i].item.Tag {
-			inv.items[i].item = item
-			inv.items[i].staxer = NewStaxer(item.Tag)
+			inv.items[i].staxer = NewStaxer(res.GetItem(item.Tag).Stax)
+		}
+		inv.items[i].item = item
+	}
+	inv.page = min(inv.page, inv.pages()-1)
+}
+
+// syncHeld keeps what is drawn in hand to what the player holds.
+func (inv *Inventory) syncHeld(pc *PlayerController) {
+	if pc.heldItem != inv.held.item.Tag {
+		inv.held.item.Tag = pc.heldItem
+		if pc.heldItem != "" {
+			inv.held.staxer = NewStaxer(res.GetItem(pc.heldItem).Stax)
 		}
 	}
+	inv.dragging = pc.dragging
+}
+
+// reloadStax refreshes any item staxers using the named stax.
+func (inv *Inventory) reloadStax(name string) {
+	for i := range inv.items {
+		inv.items[i].staxer.reloadStax(name)
+	}
+	inv.held.staxer.reloadStax(name)
+}
+
+// reloadItems clears the item staxers and examined text, so they are made again on the next sync.
+func (inv *Inventory) reloadItems() {
+	inv.items = nil
+	inv.held.item.Tag = ""
+	inv.examined = ""
+}
+
+// contains returns if the point is over the orb.
+func (inv *Inventory) contains(x, y float64) bool {
+	return len(inv.items) > 0 && x >= inv.X() && x <= inv.X()+inv.width && y >= inv.Y() && y <= inv.Y()+inv.height
+}
+
+// combine tries putting the held item together with the one at index, saying no if they don't go together.
+func (inv *Inventory) combine(pc *PlayerController, index int) []Change {
+	tag := inv.items[index].item.Tag
+	held := pc.heldItem
+	pc.heldItem = ""
+	pc.dragging = false
+	if _, ok := res.Combine(held, tag); !ok {
+		inv.examined = "イイエ"
+		return nil
+	}
+	inv.examined = ""
+	return []Change{&ChangeCombineItems{Tag: held, With: tag}, &ChangeSound{Name: "use"}}
 }
 
 func (inv *Inventory) Update(ctx *ContextGame) []Change {
@@ -88,45 +176,109 @@ func (inv *Inventory) Update(ctx *ContextGame) []Change {
 	inv.SetY(h - inv.height - 8)
 
 	// I guess just get our target and sync to it.
-	if t, ok := ctx.Referables.ByFirstTag(inv.targetTag).(*Thinger); ok {
-		inv.SyncTo(t.Storagable)
+	t, ok := ctx.Referables.ByFirstTag(inv.targetTag).(*Thinger)
+	if !ok {
+		return nil
+	}
+	inv.SyncTo(t.Storagable)
+	pc, _ := t.controller.(*PlayerController) // hackiness abounds.
+	if pc != nil {
+		// Anything used up or combined away can't be held anymore.
+		if pc.heldItem != "" && !t.HasItem(pc.heldItem) {
+			pc.heldItem = ""
+			pc.dragging = false
+		}
+		inv.syncHeld(pc)
+	}
+	if ctx.InputBlocked() {
+		return nil
+	}
+
+	// Keys step through the items, turning the pages as needed, and put the pointer on them so everything else works as if it were moved there.
+	if len(inv.items) > 0 {
+		step := 0
+		if inv.input.ActionIsJustPressed(InputPrevItem) {
+			step = -1
+		} else if inv.input.ActionIsJustPressed(InputNextItem) {
+			step = 1
+		}
+		if step != 0 {
+			inv.selected = (inv.selected + step + len(inv.items)) % len(inv.items)
+			inv.page = inv.selected / inventorySlots
+			x, y, x2, y2 := inv.ItemBounds(inv.selected)
+			ctx.Pointer.MoveTo((x+x2)/2*ctx.Zoom, (y+y2)/2*ctx.Zoom)
+		}
+	}
+
+	x, y := ctx.UIMousePosition()
+	inv.pointerX, inv.pointerY = x, y
+	last := inv.hovered
+	inv.hovered = -1
+	for index := range inv.items {
+		if !inv.onPage(index) {
+			continue
+		}
+		ix, iy, ix2, iy2 := inv.ItemBounds(index)
+		if x >= ix && x <= ix2 && y >= iy && y <= iy2 {
+			inv.hovered = index
+			inv.selected = index
+		}
+	}
+	if inv.hovered != last {
+		inv.examined = ""
 	}
 
-	x, y := ctx.MousePosition()
-	inv.hoveredName = ""
-	if t, ok := ctx.Referables.ByFirstTag(inv.targetTag).(*Thinger); ok {
-		if t.controller != nil {
+	// The player doesn't wander off when the orb is being poked at.
+	over := inv.contains(x, y)
+	if t.controller != nil {
+		if over {
+			t.controller.Block()
+		} else {
 			t.controller.Unblock()
 		}
-		pc := t.controller.(*PlayerController) // hackiness abounds.
-		inv.heldItemIndex = -1
-		for index, item := range t.Storagable {
-			ix, iy, ix2, iy2 := inv.ItemBounds(index)
-			if x >= ix && x <= ix2 && y >= iy && y <= iy2 {
-				inv.hoveredName = item.Name
-				// Get our cursor and show pickup.
-				if c, ok := ctx.Referables.ByFirstTag("cursor").(*Thinger); ok {
-					c.Animation("grab")
-				}
-				if t.controller != nil {
-					t.controller.Block()
-				}
-				if pc != nil {
-					if pc.input.ActionIsPressed(InputMoveTo) {
-						pc.heldItem = &inv.items[index] // uh-oh!!!
-					}
-				}
-			}
-			if pc != nil && pc.heldItem != nil {
-				if pc.heldItem == &inv.items[index] {
-					inv.heldItemIndex = index
-				}
+	}
+
+	var changes []Change
+	if inv.hovered >= 0 {
+		item := inv.items[inv.hovered].item
+		// Get our cursor and show pickup.
+		if c, ok := ctx.Referables.ByFirstTag("cursor").(*Thinger); ok {
+			c.Animation("grab")
+		}
+		if ctx.Pointer.Looked() || ctx.Pointer.Cancelled() {
+			inv.examined = res.GetText(res.GetItem(item.Tag).Description)
+		}
+		if pc != nil {
+			switch {
+			case ctx.Pointer.Pressed() && pc.heldItem == "":
+				// Pick it up, to be dragged off or let go of to keep in hand.
+				pc.heldItem = item.Tag
+				pc.dragging = true
+			case ctx.Pointer.Pressed() && pc.heldItem != item.Tag:
+				changes = append(changes, inv.combine(pc, inv.hovered)...)
+			case ctx.Pointer.Dropped() && pc.dragging && pc.heldItem != item.Tag:
+				changes = append(changes, inv.combine(pc, inv.hovered)...)
+			case ctx.Pointer.Dropped() && pc.dragging:
+				// Let go of where it was picked up, so it stays in hand until used.
+				pc.dragging = false
 			}
 		}
+	} else if over && pc != nil {
+		switch {
+		case ctx.Pointer.Dropped() && pc.dragging:
+			// Dropped back on the orb, so put it back.
+			pc.heldItem = ""
+			pc.dragging = false
+		case ctx.Pointer.Pressed() && pc.heldItem == "" && inv.pages() > 1:
+			inv.page = (inv.page + 1) % inv.pages()
+		}
+	}
+	if pc != nil {
+		inv.syncHeld(pc)
 	}
 
 	// Fade in the orb while hovered.
-	if x >= inv.X() && x <= inv.X()+inv.width && y >= inv.Y() && y <= inv.Y()+inv.height {
+	if over {
 		if inv.fade < fadeMax {
 			inv.fade++
 		}
@@ -136,72 +288,82 @@ func (inv *Inventory) Update(ctx *ContextGame) []Change {
 		}
 	}
 
-	return nil
+	return changes
 }
 
 func (inv *Inventory) Draw(ctx *context.Draw) {
-	if len(inv.items) == 0 {
+	if len(inv.items) == 0 && inv.held.item.Tag == "" {
 		return
 	}
-	{
+	alpha := float32(inv.fade) / fadeMax
+	clr := color.NRGBA{139, 98, 16, uint8(alpha * 255)}
+	if len(inv.items) > 0 {
 		op := &ebiten.DrawImageOptions{}
 		op.GeoM.Translate(inv.X(), inv.Y())
 		op.GeoM.Concat(ctx.Op.GeoM)
-		op.ColorScale.ScaleAlpha(float32(inv.fade) / fadeMax)
+		op.ColorScale.ScaleAlpha(alpha)
+		ctx.Flush()
 		ctx.Target.DrawImage(res.Images["orb"], op)
 	}
 
 	for index, item := range inv.items {
+		if !inv.onPage(index) {
+			continue
+		}
 		op := &ebiten.DrawImageOptions{}
-		ix, iy, _, _ := inv.ItemBounds(index)
+		ix, iy, ix2, iy2 := inv.ItemBounds(index)
 		op.GeoM.Translate(ix, iy)
 		op.GeoM.Concat(ctx.Op.GeoM)
-		inv.DrawItem(ctx, op, item, true)
+		// The one being dragged out is left as a ghost.
+		itemAlpha := alpha
+		if inv.dragging && item.item.Tag == inv.held.item.Tag {
+			itemAlpha /= 2
+		}
+		inv.DrawItem(ctx, op, item, itemAlpha)
+
+		if item.item.Count > 1 {
+			geom := ebiten.GeoM{}
+			geom.Translate(ix2, iy2-4)
+			geom.Concat(ctx.Op.GeoM)
+			ctx.Text(fmt.Sprint(item.item.Count), geom, clr)
+		}
+	}
+
+	// Show which page we're on in the middle of the orb.
+	if inv.pages() > 1 {
+		geom := ebiten.GeoM{}
+		geom.Translate(inv.X()+inv.width/2, inv.Y()+inv.height/2-6)
+		geom.Concat(ctx.Op.GeoM)
+		ctx.Text(fmt.Sprintf("%d/%d", inv.page+1, inv.pages()), geom, clr)
 	}
 
-	if inv.hoveredName != "" {
+	if inv.hovered >= 0 {
 		geom := ebiten.GeoM{}
-		geom.Translate(ctx.MousePosition())
+		geom.Translate(inv.pointerX, inv.pointerY)
 		geom.Translate(0, -16)
 		geom.Concat(ctx.Op.GeoM)
-		alpha := float32(inv.fade) / fadeMax
-		ctx.Text(inv.hoveredName, geom, color.NRGBA{139, 98, 16, uint8(alpha * 255)})
+		ctx.Text(res.GetText(res.GetItem(inv.items[inv.hovered].item.Tag).Name), geom, clr)
+		if inv.examined != "" {
+			geom.Translate(0, -10*ctx.Op.GeoM.Element(1, 1))
+			ctx.Text(inv.examined, geom, clr)
+		}
 	}
 
-	if inv.heldItemIndex != -1 {
+	if inv.held.item.Tag != "" {
 		op := &ebiten.DrawImageOptions{}
-		op.GeoM.Translate(ctx.MousePosition())
+		op.GeoM.Translate(inv.pointerX, inv.pointerY)
 		op.GeoM.Translate(0, -8)
-		op.GeoM.Translate(-float64(inv.items[inv.heldItemIndex].staxer.stax.Stax.SliceWidth/2), -float64(inv.items[inv.heldItemIndex].staxer.stax.Stax.SliceHeight/2))
+		op.GeoM.Translate(-float64(inv.held.staxer.stax.Stax.SliceWidth/2), -float64(inv.held.staxer.stax.Stax.SliceHeight/2))
 		op.GeoM.Concat(ctx.Op.GeoM)
-		inv.DrawItem(ctx, op, inv.items[inv.heldItemIndex], false)
+		inv.DrawItem(ctx, op, inv.held, 1)
 	}
 }
 
-func (inv *Inventory) DrawItem(ctx *context.Draw, op *ebiten.DrawImageOptions, item InvItem, fade bool) {
-	opts := &ebiten.DrawImageOptions{}
-
-	const sliceDistance = 1
-	sliceDistanceEnd := math.Max(1, sliceDistance*op.GeoM.Element(0, 0))
-
-	for i, slice := range item.staxer.frame.Slices {
-		for j := 0; j < int(sliceDistanceEnd); j++ {
-			opts.GeoM.Reset()
-
-			opts.GeoM.Translate(0, -sliceDistance*float64(i))
-
-			opts.GeoM.Concat(op.GeoM)
-
-			opts.GeoM.Translate(0, float64(j))
-			if fade {
-				opts.ColorScale.ScaleAlpha(float32(inv.fade) / fadeMax)
-			}
-
-			opts.Blend = ctx.Op.Blend
-			sub := item.staxer.stax.EbiImage.SubImage(image.Rect(slice.X, slice.Y, slice.X+item.staxer.stax.Stax.SliceWidth, slice.Y+item.staxer.stax.Stax.SliceHeight)).(*ebiten.Image)
-			ctx.Target.DrawImage(sub, opts)
-		}
-	}
+func (inv *Inventory) DrawItem(ctx *context.Draw, op *ebiten.DrawImageOptions, item InvItem, alpha float32) {
+	sprite := item.staxer.sprite()
+	sprite.SliceDistance = 1
+	sprite.ColorScale.ScaleAlpha(alpha)
+	ctx.Batch.Add(ctx.Target, &sprite, op.GeoM, ctx.Op.Blend)
 }
 
 /*func (inv *Inventory) Resize(width, height int) {
@@ -0,0 +1,117 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/ehh24/pkg/game/ables"
+	"github.com/kettek/ehh24/pkg/game/context"
+	"github.com/kettek/ehh24/pkg/menu"
+	"github.com/kettek/ehh24/pkg/res"
+	"github.com/kettek/ehh24/pkg/statemachine"
+)
+
+// inventoryPreviewScale is how much bigger the selected item is shown.
+const inventoryPreviewScale = 4
+
+// InventoryState is a screen listing the player's items over the frozen game, and what the selected one is. Picking one pops back with its tag, so it can be held.
+type InventoryState struct {
+	menu         *menu.Menu
+	storage      ables.Storagable
+	staxers      []Staxer
+	descriptions []string
+	rotation     float64
+	dctx         context.Draw
+}
+
+// NewInventoryState makes an inventory screen. The storage to show is passed in when it is entered.
+func NewInventoryState() *InventoryState {
+	s := &InventoryState{}
+	s.menu = menu.New("Inventory")
+	s.menu.Back = func() statemachine.State {
+		return statemachine.Pop(nil)
+	}
+	return s
+}
+
+// Init is called when the state is to be first entered.
+func (s *InventoryState) Init() {
+	ebiten.SetCursorMode(ebiten.CursorModeVisible)
+}
+
+// OnEnter takes the storage to list.
+func (s *InventoryState) OnEnter(data any) {
+	s.storage, _ = data.(ables.Storagable)
+	s.staxers = nil
+	s.descriptions = nil
+	s.menu.Items = nil
+	for _, item := range s.storage {
+		def := res.GetItem(item.Tag)
+		s.staxers = append(s.staxers, NewStaxer(def.Stax))
+		s.descriptions = append(s.descriptions, res.GetText(def.Description))
+		s.menu.Items = append(s.menu.Items, menu.Item{
+			Label: fmt.Sprintf("%s x%d", res.GetText(def.Name), item.Count),
+			Activate: func() statemachine.State {
+				return statemachine.Pop(item.Tag)
+			},
+		})
+	}
+	if len(s.menu.Items) == 0 {
+		s.menu.Items = append(s.menu.Items, menu.Item{Label: "Nothing"})
+	}
+}
+
+// Update updates the menu and spins the selected item around.
+func (s *InventoryState) Update() statemachine.State {
+	s.rotation += 0.02
+	return s.menu.Update()
+}
+
+// Draw draws the list, with the selected item and what it is off to the side of it.
+func (s *InventoryState) Draw(screen *ebiten.Image) {
+	s.menu.Draw(screen)
+	if s.menu.Selected < 0 || s.menu.Selected >= len(s.staxers) {
+		return
+	}
+
+	drawItemPreview(&s.dctx, screen, s.staxers[s.menu.Selected], s.rotation, s.descriptions[s.menu.Selected])
+}
+
+// drawItemPreview draws an item big and spinning to the left of a menu, with its description under it.
+func drawItemPreview(dctx *context.Draw, screen *ebiten.Image, staxer Staxer, rotation float64, description string) {
+	zoom := statemachine.Zoom()
+	dctx.Target = screen
+	dctx.Op = &ebiten.DrawImageOptions{}
+
+	sprite := staxer.sprite()
+	sprite.SliceDistance = 1
+	sprite.CenterX = 0.5
+	sprite.CenterY = 0.5
+	sprite.OriginX = -0.5
+	sprite.OriginY = -0.5
+	sprite.Rotation = rotation
+
+	geom := ebiten.GeoM{}
+	geom.Scale(inventoryPreviewScale, inventoryPreviewScale)
+	geom.Translate(float64(screen.Bounds().Dx())/zoom/4, float64(screen.Bounds().Dy())/zoom/2)
+	geom.Scale(zoom, zoom)
+	dctx.Batch.Add(screen, &sprite, geom, dctx.Op.Blend)
+	dctx.Flush()
+
+	if description != "" {
+		dctx.Op.GeoM.Scale(zoom, zoom)
+		geom.Reset()
+		geom.Translate(float64(screen.Bounds().Dx())/zoom/4, float64(screen.Bounds().Dy())/zoom/2+inventoryPreviewScale*8)
+		geom.Scale(zoom, zoom)
+		dctx.Text(description, geom, color.White)
+	}
+}
+
+// Layout does a layout.
+func (s *InventoryState) Layout(ow, oh int) (int, int) {
+	return ow, oh
+}
+
+// Overlay is drawn over the game.
+func (s *InventoryState) Overlay() {}
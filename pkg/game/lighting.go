@@ -0,0 +1,213 @@
+package game
+
+import (
+	_ "embed"
+	"image"
+	"image/color"
+	"math"
+	"math/rand/v2"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/ehh24/pkg/res"
+)
+
+//go:embed light.kage
+var lightShaderSource []byte
+
+// lightReveal is how far past a wall's edge light carries, to light up its face. It is kept under the thinnest walls' width.
+const lightReveal = 2
+
+// Light is a light source in za warudo.
+type Light struct {
+	Kind        res.LightKind
+	X, Y        float64
+	Color       color.NRGBA
+	Radius      float64
+	Angle       float64
+	TargetAngle float64 // Angle is eased towards this.
+	Spread      float64
+	Flicker     float64
+	Tag         string
+	flicker     float64 // Current flicker dimming.
+}
+
+// NewLight makes a light from a place's light.
+func NewLight(l *res.Light) *Light {
+	return &Light{
+		Kind:        l.Kind,
+		X:           float64(l.Point.X),
+		Y:           float64(l.Point.Y),
+		Color:       l.Color,
+		Radius:      l.Radius,
+		Angle:       l.Angle,
+		TargetAngle: l.Angle,
+		Spread:      l.Spread,
+		Flicker:     l.Flicker,
+		Tag:         l.Tag,
+	}
+}
+
+// Update flickers the light and turns it towards its target angle.
+func (l *Light) Update() {
+	if l.Flicker > 0 {
+		l.flicker += (rand.Float64()*l.Flicker - l.flicker) * 0.3
+	}
+
+	// Same as ever, take the short way around.
+	diff := math.Remainder(l.TargetAngle-l.Angle, math.Pi*2)
+	if math.Abs(diff) > 0.05 {
+		l.Angle = math.Remainder(l.Angle+diff/10, math.Pi*2)
+	}
+}
+
+// spread returns the light's spread, being a full turn for point lights.
+func (l *Light) spread() float64 {
+	if l.Kind == res.LightKindCone {
+		return l.Spread
+	}
+	return math.Pi * 2
+}
+
+// How far and wide the player can see, in art pixels.
+const (
+	viewRadius = 240
+	viewNear   = 24 // Radius around the eye that is always seen, walls permitting.
+	viewSpread = math.Pi / 4
+)
+
+// NewViewLights makes the lights that make up the player's view: a cone where they look and a little glow around their eye.
+func NewViewLights() []*Light {
+	return []*Light{
+		{
+			Kind:   res.LightKindCone,
+			Color:  color.NRGBA{255, 250, 235, 255},
+			Radius: viewRadius,
+			Spread: viewSpread,
+		},
+		{
+			Kind:   res.LightKindPoint,
+			Color:  color.NRGBA{255, 250, 235, 160},
+			Radius: viewNear,
+		},
+	}
+}
+
+// Lighting renders the place's lights, along with the player's view, into a light map that is multiplied over the world.
+type Lighting struct {
+	View     []*Light // The player's view, which only shines in places with a limited view.
+	canvas   *ebiten.Image
+	lightmap *ebiten.Image // The part of canvas being drawn to.
+	shader   *ebiten.Shader
+	vertices []ebiten.Vertex
+	indices  []uint16
+}
+
+// NewLighting makes our lighting.
+func NewLighting() *Lighting {
+	shader, err := ebiten.NewShader(lightShaderSource)
+	if err != nil {
+		panic(err)
+	}
+	return &Lighting{
+		shader: shader,
+	}
+}
+
+// Update updates our lights and the place's.
+func (l *Lighting) Update(ctx *ContextGame) {
+	for _, light := range l.View {
+		light.Update()
+	}
+	for _, light := range ctx.Place.lights {
+		light.Update()
+	}
+}
+
+// Draw multiplies the light map over the target, with geom taking the world to it. Places without an ambient color are left as they are, unless their view is limited, in which case anything unlit is left in the dark.
+func (l *Lighting) Draw(ctx *ContextGame, target *ebiten.Image, geom ebiten.GeoM) {
+	ambient := ctx.Place.ambient
+	if ambient.A == 0 {
+		if !ctx.Place.limitedView {
+			return
+		}
+		ambient = color.NRGBA{0, 0, 0, 255}
+	}
+
+	// The light map is only ever grown, and just its top left is used, so a smaller target doesn't make a new one.
+	w, h := target.Bounds().Dx(), target.Bounds().Dy()
+	if l.canvas == nil {
+		l.canvas = ebiten.NewImage(w, h)
+	} else if cw, ch := l.canvas.Bounds().Dx(), l.canvas.Bounds().Dy(); cw < w || ch < h {
+		l.canvas.Dispose()
+		l.canvas = ebiten.NewImage(max(cw, w), max(ch, h))
+	}
+	l.lightmap = l.canvas.SubImage(image.Rect(0, 0, w, h)).(*ebiten.Image)
+	l.lightmap.Fill(color.NRGBA{ambient.R, ambient.G, ambient.B, 255})
+
+	segments := blockSegments(ctx.Place)
+	scale := geom.Element(0, 0)
+
+	lights := ctx.Place.lights
+	if ctx.Place.limitedView {
+		lights = append(lights[:len(lights):len(lights)], l.View...)
+	}
+	for _, light := range lights {
+		l.drawLight(light, segments, geom, scale)
+	}
+
+	op := &ebiten.DrawImageOptions{}
+	op.Blend = ebiten.Blend{
+		BlendFactorSourceRGB:        ebiten.BlendFactorZero,
+		BlendFactorSourceAlpha:      ebiten.BlendFactorZero,
+		BlendFactorDestinationRGB:   ebiten.BlendFactorSourceColor,
+		BlendFactorDestinationAlpha: ebiten.BlendFactorOne,
+		BlendOperationRGB:           ebiten.BlendOperationAdd,
+		BlendOperationAlpha:         ebiten.BlendOperationAdd,
+	}
+	target.DrawImage(l.lightmap, op)
+}
+
+// drawLight adds a light, cut to what it can reach, to the light map.
+func (l *Lighting) drawLight(light *Light, segments []segment, geom ebiten.GeoM, scale float64) {
+	outline := visibilityPolygon(light.X, light.Y, light.Radius, light.Angle, light.spread(), lightReveal, segments)
+	if len(outline) < 2 {
+		return
+	}
+
+	l.vertices = l.vertices[:0]
+	l.indices = l.indices[:0]
+
+	cx, cy := geom.Apply(light.X, light.Y)
+	l.vertices = append(l.vertices, ebiten.Vertex{DstX: float32(cx), DstY: float32(cy)})
+	for _, p := range outline {
+		x, y := geom.Apply(p.x, p.y)
+		l.vertices = append(l.vertices, ebiten.Vertex{DstX: float32(x), DstY: float32(y)})
+	}
+	count := len(outline)
+	last := count
+	if light.spread() >= math.Pi*2 {
+		last++ // Close the loop.
+	}
+	for i := 1; i < last; i++ {
+		a := i
+		b := i%count + 1
+		l.indices = append(l.indices, 0, uint16(a), uint16(b))
+	}
+
+	brightness := float32(1 - light.flicker)
+	op := &ebiten.DrawTrianglesShaderOptions{}
+	op.Blend = ebiten.BlendLighter
+	op.Uniforms = map[string]any{
+		"Center": []float32{float32(cx), float32(cy)},
+		"Radius": float32(light.Radius * scale),
+		"Color": []float32{
+			float32(light.Color.R) / 255 * float32(light.Color.A) / 255 * brightness,
+			float32(light.Color.G) / 255 * float32(light.Color.A) / 255 * brightness,
+			float32(light.Color.B) / 255 * float32(light.Color.A) / 255 * brightness,
+			float32(light.Color.A) / 255 * brightness,
+		},
+		"Angle":  float32(light.Angle),
+		"Spread": float32(light.spread()),
+	}
+	l.lightmap.DrawTrianglesShader(l.vertices, l.indices, l.shader, op)
+}
@@ -0,0 +1,6 @@
+//go:build !debug
+
+package game
+
+// debugLog does nothing outside of debug builds.
+func debugLog(a ...any) {}
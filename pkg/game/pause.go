@@ -0,0 +1,87 @@
+package game
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/ehh24/pkg/game/ables"
+	"github.com/kettek/ehh24/pkg/menu"
+	"github.com/kettek/ehh24/pkg/settings"
+	"github.com/kettek/ehh24/pkg/statemachine"
+)
+
+// quitToTitle is popped back to the game to have it quit to the title.
+type quitToTitle struct{}
+
+// PauseState is the menu over the frozen game.
+type PauseState struct {
+	menu    *menu.Menu
+	storage ables.Storagable
+	popWith any // Data from a state popped back to us that should go on down to the game.
+}
+
+// NewPauseState makes a pause menu, with the player's storage for the inventory screen.
+func NewPauseState(storage ables.Storagable) *PauseState {
+	p := &PauseState{
+		storage: storage,
+	}
+	p.menu = menu.New("Paused",
+		menu.Item{
+			Label: "Resume",
+			Activate: func() statemachine.State {
+				return statemachine.Pop(nil)
+			},
+		},
+		menu.Item{
+			Label: "Inventory",
+			Activate: func() statemachine.State {
+				return statemachine.Push(NewInventoryState(), p.storage)
+			},
+		},
+		menu.Item{
+			Label: "Settings",
+			Activate: func() statemachine.State {
+				return statemachine.Push(settings.NewState(), nil)
+			},
+		},
+		menu.Item{
+			Label: "Quit to Title",
+			Activate: func() statemachine.State {
+				return statemachine.Pop(quitToTitle{})
+			},
+		},
+	)
+	p.menu.Back = func() statemachine.State {
+		return statemachine.Pop(nil)
+	}
+	return p
+}
+
+// Init is called when the state is to be first entered.
+func (p *PauseState) Init() {
+	ebiten.SetCursorMode(ebiten.CursorModeVisible)
+}
+
+// OnResume passes along an item picked in the inventory screen, so it ends up in the player's hand.
+func (p *PauseState) OnResume(data any) {
+	p.popWith = data
+}
+
+// Update updates the menu.
+func (p *PauseState) Update() statemachine.State {
+	if p.popWith != nil {
+		return statemachine.Pop(p.popWith)
+	}
+	return p.menu.Update()
+}
+
+// Draw draws the menu.
+func (p *PauseState) Draw(screen *ebiten.Image) {
+	p.menu.Draw(screen)
+}
+
+// Layout does a layout.
+func (p *PauseState) Layout(ow, oh int) (int, int) {
+	return ow, oh
+}
+
+// Overlay is drawn over the game.
+func (p *PauseState) Overlay() {}
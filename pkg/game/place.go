@@ -1,17 +1,35 @@
 package game
 
 import (
+	"fmt"
+	"image"
+	"image/color"
+	"slices"
+
 	"github.com/kettek/ehh24/pkg/game/ables"
+	"github.com/kettek/ehh24/pkg/post"
 	"github.com/kettek/ehh24/pkg/res"
 	"github.com/traefik/yaegi/interp"
 )
 
 // Place is where things do be happen, tho.
 type Place struct {
-	Name       string
-	referables Referables // Da referables in da place.
-	areas      []*Area    // Da collision areas.
-	entered    bool
+	Name        string
+	Bounds      image.Rectangle // Area the camera is kept within.
+	lights      []*Light
+	ambient     color.NRGBA
+	limitedView bool
+	weather     res.Weather
+	music       string
+	ambience    string
+	post        *post.Chain
+	key         string     // Da res name we were loaded from.
+	referables  Referables // Da referables in da place.
+	areas       []*Area    // Da collision areas.
+	containers  []*Container
+	entered     bool
+	loaded      []int           // IDs of referables that came from the place file, so we know what to replace on reload.
+	removed     map[string]bool // Tags of areas and referables removed during play, so reloads don't bring them back.
 	// interpreter stuff
 	interp  *interp.Interpreter
 	OnEnter func(p *Place)
@@ -21,37 +39,92 @@ type Place struct {
 
 // NewPlace does a thingie.
 func NewPlace(name string) *Place {
-	p := &Place{}
+	p := &Place{
+		key:     name,
+		removed: make(map[string]bool),
+	}
 
 	// Load from res.
-	rp, ok := res.Places[name]
-	if !ok {
-		panic("place not found: " + name)
+	rp, err := res.GetPlace(name)
+	if err != nil {
+		panic(name + ": " + err.Error())
 	}
-	p.Name = rp.Name
 
-	// Setup interpreter stuff
-	if script, ok := res.Scripts[name]; ok {
-		p.interp = interp.New(interp.Options{})
-		setupInterp(p.interp, script)
-		if fn, err := p.interp.Eval("Enter"); err == nil {
-			p.OnEnter = fn.Interface().(func(p *Place))
-		}
-		if fn, err := p.interp.Eval("Leave"); err == nil {
-			p.OnLeave = fn.Interface().(func(p *Place))
-		}
-		if fn, err := p.interp.Eval("Tick"); err == nil {
-			p.OnTick = fn.Interface().(func(p *Place))
+	p.loadScript()
+	p.load(rp)
+
+	return p
+}
+
+// Reload rebuilds the place's referables and areas from res and re-evaluates its script. Referables that were not loaded from the place file, such as the player, are kept.
+func (p *Place) Reload() {
+	rp, err := res.GetPlace(p.key)
+	if err != nil {
+		return
+	}
+
+	kept := p.referables[:0]
+	for _, r := range p.referables {
+		if !slices.Contains(p.loaded, r.ID()) {
+			kept = append(kept, r)
 		}
 	}
+	p.referables = kept
+	p.areas = nil
+	p.lights = nil
+	p.loaded = nil
+
+	p.loadScript()
+	p.load(rp)
+}
+
+// loadScript sets up the interpreter from the place's script, if it has one.
+func (p *Place) loadScript() {
+	p.interp = nil
+	p.OnEnter = nil
+	p.OnLeave = nil
+	p.OnTick = nil
+
+	script, ok := res.Scripts[p.key]
+	if !ok {
+		return
+	}
+	p.interp = interp.New(interp.Options{})
+	setupInterp(p.interp, script)
+	if fn, err := p.interp.Eval("Enter"); err == nil {
+		p.OnEnter = fn.Interface().(func(p *Place))
+	}
+	if fn, err := p.interp.Eval("Leave"); err == nil {
+		p.OnLeave = fn.Interface().(func(p *Place))
+	}
+	if fn, err := p.interp.Eval("Tick"); err == nil {
+		p.OnTick = fn.Interface().(func(p *Place))
+	}
+}
+
+// load adds the floors, statics, layers, and areas from the res place.
+func (p *Place) load(rp res.Place) {
+	p.Name = rp.Name
+	p.Bounds = rp.Bounds
+	p.limitedView = rp.LimitedView
+	p.weather = rp.Weather
+	p.music = rp.Music
+	p.ambience = rp.Ambience
+	p.post = post.NewChain(rp.Effects)
+	if p.Bounds.Empty() {
+		p.Bounds = rp.Extents()
+	}
+
+	var loaded Referables
 
 	// Load in the floors.
 	for _, floor := range rp.Floor {
 		fl := NewFloor(floor.Name)
 		fl.SetX(float64(floor.Point.X))
 		fl.SetY(float64(floor.Point.Y))
-		fl.SetPriority(ables.PriorityBack)
-		p.referables = append(p.referables, fl)
+		fl.SetPriority(layerPriority(floor.Layer, ables.PriorityBack))
+		fl.setStackAndAnimation(floor.Stack, floor.Animation)
+		loaded = append(loaded, fl)
 	}
 
 	// Load in the staticers.
@@ -59,24 +132,88 @@ func NewPlace(name string) *Place {
 		st := NewStaticer(static.Name)
 		st.SetX(float64(static.Point.X))
 		st.SetY(float64(static.Point.Y))
-		st.SetPriority(ables.PriorityMiddle)
+		st.SetPriority(layerPriority(static.Layer, ables.PriorityMiddle))
 		st.SetTag(static.Tag)
-		p.referables = append(p.referables, st)
+		st.setStackAndAnimation(static.Stack, static.Animation)
+		loaded = append(loaded, st)
+	}
+
+	// Load in the decorative layers.
+	for _, layer := range rp.Layers {
+		for _, static := range layer.Statics {
+			st := NewStaticer(static.Name)
+			st.SetX(float64(static.Point.X))
+			st.SetY(float64(static.Point.Y))
+			st.SetPriority(layerPriority(static.Layer, ables.PriorityFront))
+			st.SetTag(static.Tag)
+			st.setStackAndAnimation(static.Stack, static.Animation)
+			st.opacity = float32(layer.Opacity)
+			loaded = append(loaded, st)
+		}
+	}
+
+	// Load in the emitters.
+	for _, emitter := range rp.Emitters {
+		em := NewEmitter(emitter)
+		em.SetPriority(layerPriority(emitter.Layer, ables.PriorityFront))
+		loaded = append(loaded, em)
+	}
+
+	for _, r := range loaded {
+		if r.Tag() != "" && p.removed[r.Tag()] {
+			continue
+		}
+		p.loaded = append(p.loaded, r.ID())
+		p.referables = append(p.referables, r)
+	}
+
+	// Load in lights.
+	p.ambient = rp.Ambient
+	for _, light := range rp.Lights {
+		p.lights = append(p.lights, NewLight(light))
 	}
 
 	// Load in things.
 	// TODO!
 
+	// Load in containers, keeping what is in any we already had.
+	old := p.containers
+	p.containers = nil
+	for _, rc := range rp.Containers {
+		c := newContainer(rc)
+		for _, o := range old {
+			if o.Tag() == rc.Tag {
+				c.Storagable = o.Storagable
+				c.triggered = o.triggered
+			}
+		}
+		p.containers = append(p.containers, c)
+	}
+
 	// Load in collision areas.
 	for _, poly := range rp.Polygons {
+		if poly.Tag != "" && p.removed[poly.Tag] {
+			continue
+		}
 		area := &Area{
 			original: poly,
 		}
 
 		p.areas = append(p.areas, area)
 	}
+}
 
-	return p
+// layerPriority returns the priority band for the given static layer, or fallback if it is the default layer.
+func layerPriority(layer res.StaticLayer, fallback int) int {
+	switch layer {
+	case res.StaticLayerBack:
+		return ables.PriorityBack
+	case res.StaticLayerMiddle:
+		return ables.PriorityMiddle
+	case res.StaticLayerFront:
+		return ables.PriorityFront
+	}
+	return fallback
 }
 
 func (p *Place) Update(ctx *ContextGame) []Change {
@@ -89,10 +226,84 @@ func (p *Place) Update(ctx *ContextGame) []Change {
 	for _, t := range p.referables.Updateables() {
 		changes = append(changes, t.Update(ctx)...)
 	}
+	for _, c := range p.containers {
+		changes = append(changes, c.Update(ctx)...)
+	}
 
 	return changes
 }
 
+// SetWeather changes the place's weather, such as "Snow" or "Rain", easing into it.
+func (p *Place) SetWeather(kind string, intensity float64) {
+	var k res.WeatherKind
+	if err := k.UnmarshalText([]byte(kind)); err != nil {
+		fmt.Println("bad weather", err)
+		return
+	}
+	p.weather.Kind = k
+	p.weather.Intensity = intensity
+}
+
+// SetWind changes how hard the wind blows the place's weather sideways.
+func (p *Place) SetWind(wind float64) {
+	p.weather.Wind = wind
+}
+
+// SetMusic changes the place's music, crossfading into it. An empty name fades it out.
+func (p *Place) SetMusic(name string) {
+	p.music = name
+}
+
+// SetAmbience changes the place's ambience, crossfading into it. An empty name fades it out.
+func (p *Place) SetAmbience(name string) {
+	p.ambience = name
+}
+
+// SetEffect sets a param of the place's first post-processing effect of the given kind, such as "Vignette" and "Strength".
+func (p *Place) SetEffect(kind, param string, value float64) {
+	if pass := p.effect(kind); pass != nil {
+		pass.Set(param, value)
+	}
+}
+
+// TweenEffect eases a param of the place's first post-processing effect of the given kind to a value over some ticks.
+func (p *Place) TweenEffect(kind, param string, to float64, ticks int) {
+	if pass := p.effect(kind); pass != nil {
+		pass.Tween(param, to, ticks)
+	}
+}
+
+// effect returns the place's first post-processing pass of the named kind.
+func (p *Place) effect(kind string) *post.Pass {
+	var k res.EffectKind
+	if err := k.UnmarshalText([]byte(kind)); err != nil {
+		fmt.Println("bad effect", err)
+		return nil
+	}
+	var pass *post.Pass
+	if p.post != nil {
+		pass = p.post.Pass(k)
+	}
+	if pass == nil {
+		fmt.Println("no", kind, "effect in", p.Name)
+	}
+	return pass
+}
+
+// Burst bursts the first emitter with the given tag.
+func (p *Place) Burst(tag string) {
+	if e, ok := p.referables.ByFirstTag(tag).(*Emitter); ok {
+		e.Burst()
+	}
+}
+
+// SetEmitting starts or stops the first emitter with the given tag.
+func (p *Place) SetEmitting(tag string, emitting bool) {
+	if e, ok := p.referables.ByFirstTag(tag).(*Emitter); ok {
+		e.SetEnabled(emitting)
+	}
+}
+
 func (p *Place) GetAreaByFirstTag(tag string) *Area {
 	for _, area := range p.areas {
 		if area.original.Tag == tag {
@@ -105,9 +316,33 @@ func (p *Place) GetAreaByFirstTag(tag string) *Area {
 func (p *Place) RemoveAreaByFirstTag(tag string) {
 	for i, area := range p.areas {
 		if area.original.Tag == tag {
+			p.removed[tag] = true
 			p.areas = append(p.areas[:i], p.areas[i+1:]...)
 			return
 		}
 	}
-	return
+}
+
+// GetContainer returns the container with the given tag, or nil.
+func (p *Place) GetContainer(tag string) *Container {
+	for _, c := range p.containers {
+		if c.Tag() == tag {
+			return c
+		}
+	}
+	return nil
+}
+
+// ContainerHas returns if the container with the given tag has the item in it.
+func (p *Place) ContainerHas(tag, item string) bool {
+	if c := p.GetContainer(tag); c != nil {
+		return c.HasItem(item)
+	}
+	return false
+}
+
+// RemoveReferableByFirstTag removes the first referable with the given tag, remembering it so it stays removed on reload.
+func (p *Place) RemoveReferableByFirstTag(tag string) Referable {
+	p.removed[tag] = true
+	return p.referables.RemoveByFirstTag(tag)
 }
@@ -0,0 +1,184 @@
+package game
+
+import (
+	"math"
+
+	"github.com/kettek/ehh24/pkg/config"
+	"github.com/kettek/ehh24/pkg/res"
+	"github.com/kettek/ehh24/pkg/statemachine"
+	input "github.com/quasilyte/ebitengine-input"
+)
+
+// Our inputs for pointing at things and doing things with them, whether by mouse, gamepad, or touch.
+const (
+	InputPointer input.Action = iota
+	InputConfirm
+	InputCancel
+	InputLook
+	InputDrag
+)
+
+// pointerBindings are the config bindings of our inputs.
+var pointerBindings = map[input.Action]string{
+	InputPointer: "Pointer",
+	InputConfirm: "Confirm",
+	InputCancel:  "Cancel",
+	InputLook:    "Look",
+	InputDrag:    "Drag",
+}
+
+// Pointer movement, in art pixels.
+const (
+	pointerSpeed     = 3    // Per tick, with the stick all the way over.
+	pointerSnapRange = 24   // How close something to interact with has to be to pull the pointer onto it.
+	pointerSnap      = 0.25 // How much of the way there it is pulled each tick.
+)
+
+// Pointer is where the player is pointing, in screen pixels, and what they did there this tick. The mouse moves it, as does a gamepad stick, which lets go onto nearby things to interact with, and touches. Controllers should ask it rather than reading input themselves, so all of these work the same.
+type Pointer struct {
+	X, Y      float64
+	Gamepad   bool // Last moved by a stick.
+	mouseX    int
+	mouseY    int
+	input     *input.Handler
+	dragging  bool // A touch is being dragged.
+	confirmed bool
+	cancelled bool
+	looked    bool
+	holding   bool
+	pressed   bool
+	dropped   bool
+}
+
+// NewPointer makes a pointer, starting wherever the mouse is.
+func NewPointer(insys *input.System) *Pointer {
+	p := &Pointer{
+		input: insys.NewHandler(0, config.Keymap(pointerBindings)),
+	}
+	p.mouseX, p.mouseY = statemachine.CursorPosition()
+	p.X, p.Y = float64(p.mouseX), float64(p.mouseY)
+	return p
+}
+
+// Remap rebinds the pointer's inputs to the config's bindings.
+func (p *Pointer) Remap() {
+	p.input.Remap(config.Keymap(pointerBindings))
+}
+
+// Update moves the pointer with whatever moved, and works out what was done with it.
+func (p *Pointer) Update(ctx *ContextGame) {
+	mx, my := statemachine.CursorPosition()
+	if mx != p.mouseX || my != p.mouseY {
+		p.mouseX, p.mouseY = mx, my
+		p.X, p.Y = float64(mx), float64(my)
+		p.Gamepad = false
+	}
+
+	if info, ok := p.input.PressedActionInfo(InputPointer); ok {
+		p.X += info.Pos.X * pointerSpeed * ctx.Zoom
+		p.Y += info.Pos.Y * pointerSpeed * ctx.Zoom
+		p.Gamepad = true
+	} else if p.Gamepad {
+		p.snap(ctx)
+	}
+
+	// Touches have no hover, so the pointer jumps to wherever they happen.
+	p.confirmed, p.looked = false, false
+	if info, ok := p.input.JustPressedActionInfo(InputConfirm); ok {
+		p.moveTo(info)
+		p.confirmed = true
+	}
+	if info, ok := p.input.JustPressedActionInfo(InputLook); ok {
+		p.moveTo(info)
+		p.looked = true
+	}
+	// A drag picks up from where it started, and letting go drops whatever was dragged there.
+	if info, ok := p.input.JustPressedActionInfo(InputDrag); ok {
+		info.Pos = info.StartPos
+		p.moveTo(info)
+		p.dragging = true
+	} else if info, ok := p.input.PressedActionInfo(InputDrag); ok {
+		p.moveTo(info)
+		p.dragging = true
+	} else {
+		p.dragging = false
+	}
+	p.cancelled = p.input.ActionIsJustPressed(InputCancel)
+
+	// A tap is over as soon as it happens, so it is held for just the tick of it.
+	holding := p.dragging || p.input.ActionIsPressed(InputConfirm)
+	p.pressed = (holding || p.confirmed) && !p.holding
+	p.dropped = p.holding && !holding
+	p.holding = holding || p.confirmed
+
+	p.X = math.Max(0, math.Min(ctx.Width, p.X))
+	p.Y = math.Max(0, math.Min(ctx.Height, p.Y))
+}
+
+// moveTo moves the pointer to where an event happened, if it happened somewhere, such as a tap.
+func (p *Pointer) moveTo(info input.EventInfo) {
+	if !info.HasPos() {
+		return
+	}
+	p.X, p.Y = statemachine.ToCanvas(info.Pos.X, info.Pos.Y)
+	p.Gamepad = false
+}
+
+// Confirmed returns if something was clicked, tapped, dropped, or confirmed with a button this tick.
+func (p *Pointer) Confirmed() bool {
+	return p.confirmed
+}
+
+// Cancelled returns if the player asked to put back what they're holding or stop what they're doing.
+func (p *Pointer) Cancelled() bool {
+	return p.cancelled
+}
+
+// Looked returns if the player asked to look at what is pointed at without going to it, such as with a long press.
+func (p *Pointer) Looked() bool {
+	return p.looked
+}
+
+// Holding returns if the pointer is pressing on something.
+func (p *Pointer) Holding() bool {
+	return p.holding
+}
+
+// Pressed returns if the pointer started pressing on something this tick, such as to pick it up out of the inventory. Unlike Confirmed, this includes the start of a drag.
+func (p *Pointer) Pressed() bool {
+	return p.pressed
+}
+
+// Dropped returns if the pointer stopped pressing this tick, such as to drop whatever was dragged.
+func (p *Pointer) Dropped() bool {
+	return p.dropped
+}
+
+// MoveTo puts the pointer somewhere on the screen, such as onto something picked with keys.
+func (p *Pointer) MoveTo(x, y float64) {
+	p.X, p.Y = x, y
+	p.Gamepad = false
+}
+
+// snap pulls the pointer towards the closest thing to interact with in range.
+func (p *Pointer) snap(ctx *ContextGame) {
+	best := pointerSnapRange * ctx.Zoom
+	var tx, ty float64
+	found := false
+	for _, area := range ctx.Place.areas {
+		if area.original.Disabled || area.original.Kind != res.PolygonKindInteract {
+			continue
+		}
+		cx, cy := area.Center()
+		x, y := ctx.Camera.WorldToScreen(ctx, cx, cy)
+		if d := math.Hypot(x-p.X, y-p.Y); d < best {
+			best = d
+			tx, ty = x, y
+			found = true
+		}
+	}
+	if found {
+		p.X += (tx - p.X) * pointerSnap
+		p.Y += (ty - p.Y) * pointerSnap
+	}
+}
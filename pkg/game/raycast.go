@@ -0,0 +1,135 @@
+package game
+
+import (
+	"math"
+	"slices"
+
+	"github.com/kettek/ehh24/pkg/res"
+)
+
+// segment is a line that blocks sight and light.
+type segment struct {
+	x1, y1, x2, y2 float64
+}
+
+// point is a point, wow.
+type point struct {
+	x, y float64
+}
+
+// blockSegments returns the edges of the place's enabled Block areas.
+func blockSegments(p *Place) []segment {
+	var segments []segment
+	for _, area := range p.areas {
+		if area.original.Kind != res.PolygonKindBlock || area.original.Disabled {
+			continue
+		}
+		points := area.original.Points
+		for i := range points {
+			a := points[i]
+			b := points[(i+1)%len(points)]
+			segments = append(segments, segment{float64(a.X), float64(a.Y), float64(b.X), float64(b.Y)})
+		}
+	}
+	return segments
+}
+
+// visibilityPolygon returns the outline of what can be seen from x, y within radius, with segments blocking the view. Rays that hit a segment carry on for up to reveal more, stopping at the next segment, so the faces of walls can be seen but not what is behind them. If spread is less than a full turn, only the cone of that width around angle is cast and the outline starts at x, y. The outline is in order of angle.
+func visibilityPolygon(x, y, radius, angle, spread, reveal float64, segments []segment) []point {
+	cone := spread < math.Pi*2
+
+	// Box in the radius so every ray hits something.
+	box := []segment{
+		{x - radius, y - radius, x + radius, y - radius},
+		{x + radius, y - radius, x + radius, y + radius},
+		{x + radius, y + radius, x - radius, y + radius},
+		{x - radius, y + radius, x - radius, y - radius},
+	}
+	var nearby []segment
+	for _, s := range segments {
+		if math.Max(s.x1, s.x2) < x-radius || math.Min(s.x1, s.x2) > x+radius || math.Max(s.y1, s.y2) < y-radius || math.Min(s.y1, s.y2) > y+radius {
+			continue
+		}
+		nearby = append(nearby, s)
+	}
+	nearby = append(nearby, box...)
+
+	// Cast at every corner, and just to either side of it so we see past it.
+	const nudge = 0.0001
+	var angles []float64
+	addAngle := func(a float64) {
+		if cone {
+			a = math.Remainder(a-angle, math.Pi*2)
+			if a < -spread/2 || a > spread/2 {
+				return
+			}
+		}
+		angles = append(angles, a)
+	}
+	for _, s := range nearby {
+		for _, c := range []point{{s.x1, s.y1}, {s.x2, s.y2}} {
+			a := math.Atan2(c.y-y, c.x-x)
+			addAngle(a - nudge)
+			addAngle(a)
+			addAngle(a + nudge)
+		}
+	}
+	if cone {
+		// Fill out the arc so it stays round.
+		const steps = 16
+		for i := 0; i <= steps; i++ {
+			angles = append(angles, -spread/2+spread*float64(i)/steps)
+		}
+	}
+	slices.Sort(angles)
+
+	var outline []point
+	if cone {
+		outline = append(outline, point{x, y})
+	}
+	for _, a := range angles {
+		if cone {
+			a += angle
+		}
+		dx, dy := math.Cos(a), math.Sin(a)
+		walls := nearby[:len(nearby)-len(box)]
+		closest := radius * 2
+		for _, s := range walls {
+			if t, ok := raySegment(x, y, dx, dy, s); ok && t < closest {
+				closest = t
+			}
+		}
+		if closest < radius*2 {
+			// Carry on into the wall, but never out its far side, however thin it is.
+			far := closest + reveal
+			for _, s := range walls {
+				if t, ok := raySegment(x, y, dx, dy, s); ok && t > closest+0.01 && t < far {
+					far = t
+				}
+			}
+			closest = far
+		}
+		for _, s := range box {
+			if t, ok := raySegment(x, y, dx, dy, s); ok && t < closest {
+				closest = t
+			}
+		}
+		outline = append(outline, point{x + dx*closest, y + dy*closest})
+	}
+	return outline
+}
+
+// raySegment returns how far along the ray from x, y in direction dx, dy it hits the segment.
+func raySegment(x, y, dx, dy float64, s segment) (float64, bool) {
+	sx, sy := s.x2-s.x1, s.y2-s.y1
+	denom := dx*sy - dy*sx
+	if math.Abs(denom) < 1e-9 {
+		return 0, false
+	}
+	t := ((s.x1-x)*sy - (s.y1-y)*sx) / denom
+	u := ((s.x1-x)*dy - (s.y1-y)*dx) / denom
+	if t < 0 || u < 0 || u > 1 {
+		return 0, false
+	}
+	return t, true
+}
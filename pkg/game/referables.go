@@ -135,6 +135,12 @@ func (r Referables) Overlays() []Overlayable {
 	return res
 }
 
+// WorldOverlay is an overlay that belongs to the world itself, such as weather, so it is drawn before the UI rather than over everything.
+type WorldOverlay interface {
+	Overlayable
+	WorldOverlay()
+}
+
 // Debugable is a referable that can be debuggied.
 type Debugable interface {
 	String() string
@@ -0,0 +1,74 @@
+package game
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/kettek/ehh24/pkg/res"
+)
+
+// reloadInterval is how many ticks to wait between checking for changed assets.
+const reloadInterval = 30
+
+// staxReloadable is anything that holds onto stax that may need to be refreshed.
+type staxReloadable interface {
+	reloadStax(name string)
+}
+
+// reloadAssets polls for changed assets on disk and patches the running game to use them.
+func (g *State) reloadAssets() {
+	g.reloadTicker++
+	if g.reloadTicker < reloadInterval {
+		return
+	}
+	g.reloadTicker = 0
+
+	for _, name := range g.watcher.Poll() {
+		if err := res.ReadAsset(name); err != nil {
+			fmt.Println("reload", name, err)
+			continue
+		}
+		debugLog("reloaded", name, "from", res.SourceOf(name))
+		if name == res.ItemsFile || name == res.TextFile {
+			g.reloadItems()
+			continue
+		}
+		key := strings.TrimSuffix(name, path.Ext(name))
+		switch path.Ext(name) {
+		case ".png":
+			g.reloadStax(key)
+		case ".json":
+			if place, ok := g.gctx.Places[key]; ok {
+				place.Reload()
+			}
+		case ".txt":
+			if place, ok := g.gctx.Places[key]; ok {
+				place.loadScript()
+			}
+		}
+	}
+}
+
+// reloadStax refreshes every staxer using the named stax, in both the game and in all loaded places.
+func (g *State) reloadStax(name string) {
+	for _, r := range g.gctx.Referables {
+		if s, ok := r.(staxReloadable); ok {
+			s.reloadStax(name)
+		}
+	}
+	for _, place := range g.gctx.Places {
+		for _, r := range place.referables {
+			if s, ok := r.(staxReloadable); ok {
+				s.reloadStax(name)
+			}
+		}
+	}
+}
+
+// reloadItems drops what the inventory made from the old item definitions and text, so it is made again from the new ones.
+func (g *State) reloadItems() {
+	if inv, ok := g.gctx.Referables.ByFirstTag("inventory").(*Inventory); ok {
+		inv.reloadItems()
+	}
+}
@@ -0,0 +1,167 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kettek/ehh24/pkg/config"
+	"github.com/kettek/ehh24/pkg/game/ables"
+	"github.com/kettek/ehh24/pkg/res"
+)
+
+// saveName is the name of the save, kept alongside the config.
+const saveName = "save"
+
+// Save is the player's progress, kept between runs so the game can be continued.
+type Save struct {
+	Place  string // Place the player is in.
+	X, Y   float64
+	Items  ables.Storagable
+	Places map[string]PlaceSave // Visited places, by name.
+}
+
+// PlaceSave is how a visited place has been changed during play.
+type PlaceSave struct {
+	Removed    []string                    // Tags of removed areas and referables.
+	Disabled   map[string]bool             // Disabled state of tagged areas, by tag.
+	Animations map[string]string           // Animations of tagged staticers, by tag.
+	Emitting   map[string]bool             // Enabled state of tagged emitters, by tag.
+	Containers map[string]ables.Storagable // What is in containers, by tag.
+}
+
+// HasSave returns if there is a saved game to continue.
+func HasSave() (bool, error) {
+	data, err := config.ReadFile(saveName)
+	if err != nil {
+		return false, fmt.Errorf("save: %w", err)
+	}
+	return data != nil, nil
+}
+
+// RemoveSave removes the saved game, as when it has been finished.
+func RemoveSave() error {
+	if err := config.RemoveFile(saveName); err != nil {
+		return fmt.Errorf("save: %w", err)
+	}
+	return nil
+}
+
+// LoadState creates the game from the saved game.
+func LoadState() (*State, error) {
+	data, err := config.ReadFile(saveName)
+	if err != nil {
+		return nil, err
+	}
+	var s Save
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("save: %w", err)
+	}
+	if _, err := res.GetPlace(s.Place); err != nil {
+		return nil, fmt.Errorf("save: %w", err)
+	}
+	g := newEmptyState()
+	for name, ps := range s.Places {
+		// Places may have gone away since.
+		if _, err := res.GetPlace(name); err == nil {
+			ps.restore(g.gctx.GetPlace(name))
+		}
+	}
+	g.gctx.Place = g.gctx.GetPlace(s.Place)
+	if pl, ok := g.gctx.Referables.ByFirstTag("qi").(*Thinger); ok {
+		pl.SetX(s.X)
+		pl.SetY(s.Y)
+		pl.Storagable = s.Items
+	}
+	g.gctx.Camera.Snap()
+	return g, nil
+}
+
+// save saves the player's progress.
+func (g *State) save() error {
+	s := Save{
+		Place:  g.gctx.Place.key,
+		Places: make(map[string]PlaceSave),
+	}
+	if pl, ok := g.gctx.Referables.ByFirstTag("qi").(*Thinger); ok {
+		s.X = pl.X()
+		s.Y = pl.Y()
+		s.Items = pl.Storagable
+	}
+	for name, p := range g.gctx.Places {
+		s.Places[name] = savePlace(p)
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("save: %w", err)
+	}
+	if err := config.WriteFile(saveName, data); err != nil {
+		return fmt.Errorf("save: %w", err)
+	}
+	return nil
+}
+
+// savePlace collects how the place has been changed.
+func savePlace(p *Place) PlaceSave {
+	ps := PlaceSave{
+		Disabled:   make(map[string]bool),
+		Animations: make(map[string]string),
+		Emitting:   make(map[string]bool),
+		Containers: make(map[string]ables.Storagable),
+	}
+	for tag := range p.removed {
+		ps.Removed = append(ps.Removed, tag)
+	}
+	for _, area := range p.areas {
+		if area.original.Tag != "" {
+			ps.Disabled[area.original.Tag] = area.original.Disabled
+		}
+	}
+	for _, c := range p.containers {
+		ps.Containers[c.Tag()] = c.Storagable
+	}
+	for _, r := range p.referables {
+		if r.Tag() == "" {
+			continue
+		}
+		switch r := r.(type) {
+		case *Staticer:
+			ps.Animations[r.Tag()] = r.animation.Name
+		case *Emitter:
+			ps.Emitting[r.Tag()] = r.Enabled()
+		}
+	}
+	return ps
+}
+
+// restore puts the changes back into the freshly loaded place.
+func (ps PlaceSave) restore(p *Place) {
+	if len(ps.Removed) > 0 {
+		for _, tag := range ps.Removed {
+			p.removed[tag] = true
+		}
+		p.Reload()
+	}
+	for _, area := range p.areas {
+		if disabled, ok := ps.Disabled[area.original.Tag]; ok {
+			area.original.Disabled = disabled
+		}
+	}
+	for _, c := range p.containers {
+		if items, ok := ps.Containers[c.Tag()]; ok {
+			c.Storagable = items
+			c.settle()
+		}
+	}
+	for _, r := range p.referables {
+		switch r := r.(type) {
+		case *Staticer:
+			if anim, ok := ps.Animations[r.Tag()]; ok {
+				r.setStackAndAnimation("", anim)
+			}
+		case *Emitter:
+			if emitting, ok := ps.Emitting[r.Tag()]; ok {
+				r.SetEnabled(emitting)
+			}
+		}
+	}
+}
@@ -2,20 +2,41 @@ package game
 
 import (
 	"fmt"
+	"image"
 	"image/color"
+	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/kettek/ehh24/pkg/audio"
+	"github.com/kettek/ehh24/pkg/config"
 	"github.com/kettek/ehh24/pkg/game/ables"
 	"github.com/kettek/ehh24/pkg/game/context"
 	"github.com/kettek/ehh24/pkg/outro"
+	"github.com/kettek/ehh24/pkg/res"
 	"github.com/kettek/ehh24/pkg/statemachine"
+	"github.com/kettek/ehh24/pkg/transition"
 	input "github.com/quasilyte/ebitengine-input"
 )
 
+// Our inputs for the game itself.
+const (
+	InputMute input.Action = iota
+	InputPause
+	InputInventory
+)
+
+// gameBindings are the config bindings of our inputs.
+var gameBindings = map[input.Action]string{
+	InputMute:      "Mute",
+	InputPause:     "Pause",
+	InputInventory: "Inventory",
+}
+
 // State is our absolutely amazing game with so many features and fun.
 type State struct {
 	insys  input.System
+	input  *input.Handler
 	geom   ebiten.GeoM
 	midlay *ebiten.Image
 
@@ -23,14 +44,40 @@ type State struct {
 
 	gctx ContextGame
 	dctx context.Draw
+
+	watcher      *res.Watcher
+	reloadTicker int
+	quitting     bool   // Quitting to the title, which is under us.
+	saved        *Place // Place we were in when last saved, so entering another saves.
+	dirty        bool   // Progress has been made since the last save.
 }
 
 // NewState does exactly what you should think.
 func NewState() *State {
-	g := &State{}
+	g := newEmptyState()
+
+	// for now, just try to load in test place.
+	g.gctx.Place = g.gctx.GetPlace("cells")
+	if start := g.gctx.Place.GetAreaByFirstTag("spawn"); start != nil {
+		if pl, ok := g.gctx.Referables.ByFirstTag("qi").(*Thinger); ok {
+			cx, cy := start.Center()
+			pl.SetX(cx)
+			pl.SetY(cy)
+		}
+	}
+
+	return g
+}
+
+// newEmptyState sets up everything but the place the game starts in.
+func newEmptyState() *State {
+	g := &State{
+		watcher: res.NewWatcher(),
+	}
 	g.insys.Init(input.SystemConfig{
 		DevicesEnabled: input.AnyDevice,
 	})
+	g.input = g.insys.NewHandler(0, config.Keymap(gameBindings))
 	g.gctx.Places = make(map[string]*Place)
 	// Setup input system
 	// Make our lil cursor?
@@ -48,27 +95,20 @@ func NewState() *State {
 	t.SetPriority(ables.PriorityMiddle)
 	t.SetTag("qi")
 
-	geom := ebiten.GeoM{}
-	geom.Scale(3, 3)
-
-	g.geom = geom
+	w, h := statemachine.Size()
+	g.setZoom(statemachine.Zoom())
+	g.gctx.Camera = NewCamera(&g.insys, "qi")
+	g.gctx.Pointer = NewPointer(&g.insys)
+	g.gctx.Lighting = NewLighting()
+	g.gctx.Lighting.View = NewViewLights()
 
-	g.gctx.Zoom = g.geom.Element(0, 0)
+	weather := NewWeatherOverlay(w, h)
+	weather.SetPriority(ables.PriorityOverlay)
+	weather.SetTag("weather")
 
-	vis := NewVisibilityOverlay(320, 240)
-	vis.SetPriority(ables.PriorityOverlay + 1000)
-	vis.SetTag("visibility")
+	g.debugUI = NewTargetOverlay(w, h)
 
-	sno := NewSnoverlay(320, 240)
-	sno.SetPriority(ables.PriorityOverlay)
-	sno.SetTag("snow")
-
-	fadein := NewFadeInOverlay(320, 240, 100)
-	fadein.SetPriority(ables.PriorityOverlay + 100)
-
-	g.debugUI = NewTargetOverlay(320, 240)
-
-	g.gctx.Referables = Referables{t /*vis, sno,*/, fadein, c}
+	g.gctx.Referables = Referables{t, weather, c}
 
 	// Some boids of testing.
 	/*roboid := NewThinger("boid")
@@ -114,34 +154,55 @@ func NewState() *State {
 		g.gctx.Referables = append(g.gctx.Referables, b)
 	}*/
 
-	g.midlay = ebiten.NewImage(320, 240)
+	g.midlay = ebiten.NewImage(w, h)
 
-	inventory := NewInventory("qi")
+	// The inventory goes first, so it has its say about the pointer before the player does.
+	inventory := NewInventory(&g.insys, "qi")
 	inventory.SetPriority(ables.PriorityUI)
 	inventory.SetTag("inventory")
-	g.gctx.Referables = append(g.gctx.Referables, inventory)
-
-	// for now, just try to load in test place.
-	g.gctx.Place = NewPlace("cells")
-	g.gctx.Places["cells"] = g.gctx.Place
-	if start := g.gctx.Place.GetAreaByFirstTag("spawn"); start != nil {
-		cx, cy := start.Center()
-		t.SetX(cx)
-		t.SetY(cy)
-	}
+	g.gctx.Referables = append(Referables{inventory}, g.gctx.Referables...)
 
 	return g
 }
 
-// Init initializes the game.
+// Init initializes the game. It is called again when continued from the title.
 func (g *State) Init() {
 	ebiten.SetCursorMode(ebiten.CursorModeHidden)
+	g.remap()
 }
 
 // Update updates the game.
 func (g *State) Update() statemachine.State {
 	g.insys.Update()
 
+	if g.quitting {
+		g.quitting = false
+		if err := g.save(); err != nil {
+			fmt.Println(err)
+		}
+		// We hand ourselves to the title, so we can be continued.
+		return statemachine.WithTransition(statemachine.Pop(g), statemachine.DefaultTransition())
+	}
+
+	g.reloadAssets()
+
+	if t := g.gctx.Transition; t != nil {
+		t.Update()
+		if t.Done() {
+			g.gctx.Transition = nil
+		}
+	}
+
+	if !g.gctx.InputBlocked() {
+		if g.input.ActionIsJustPressed(InputPause) {
+			return statemachine.Push(NewPauseState(g.storage()), nil)
+		} else if g.input.ActionIsJustPressed(InputInventory) {
+			return statemachine.Push(NewInventoryState(), g.storage())
+		}
+	}
+
+	g.gctx.Pointer.Update(&g.gctx)
+
 	startProfile("update")
 	updateables := g.gctx.Referables.Updateables()
 	var changes []Change
@@ -155,15 +216,50 @@ func (g *State) Update() statemachine.State {
 	startProfile("changes")
 	for _, c := range changes {
 		c.Apply(&g.gctx)
+		switch c.(type) {
+		case *ChangeAcquireItem, *ChangeLoseItem, *ChangeCombineItems, *ChangeUse, *ChangeContainerFilled, *ChangeRemoveReferable:
+			g.dirty = true
+		}
 		// I'm sorry for this...
 		if c, ok := c.(*ChangeState); ok {
 			if c.State == "end" {
-				return outro.NewState()
+				audio.PlayMusic("")
+				audio.PlayAmbience("")
+				if err := RemoveSave(); err != nil {
+					fmt.Println(err)
+				}
+				return statemachine.WithTransition(outro.NewState(), transition.New(transition.KindDissolve, 60))
 			}
 		}
 	}
 	endProfile("changes")
 
+	if c := g.gctx.Opened; c != nil {
+		g.gctx.Opened = nil
+		if pl, ok := g.gctx.Referables.ByFirstTag("qi").(*Thinger); ok {
+			// Items may be moved in and out, so save once we're back.
+			g.dirty = true
+			return statemachine.Push(NewContainerState(c, &pl.Storagable), nil)
+		}
+	}
+
+	if g.gctx.Place != g.saved {
+		g.saved = g.gctx.Place
+		g.dirty = true
+	}
+	if g.dirty {
+		g.dirty = false
+		if err := g.save(); err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	g.gctx.Camera.Update(&g.gctx)
+	g.centerTransition()
+	g.updateAudio()
+	g.gctx.Lighting.Update(&g.gctx)
+	g.gctx.Place.post.Update()
+
 	startProfile("sort drawables")
 	// Probably shouldn't do this, but...
 	for _, t := range g.gctx.Referables.Drawables() {
@@ -180,39 +276,85 @@ func (g *State) Update() statemachine.State {
 // Draw draws the game.
 func (g *State) Draw(screen *ebiten.Image) {
 	op := &ebiten.DrawImageOptions{}
-	op.GeoM = g.geom
+	worldGeom := g.gctx.Camera.GeoM(&g.gctx)
+	uiGeom := g.geom
+
+	// A pixelated place is drawn small and scaled up by its post-processing chain.
+	chain := g.gctx.Place.post
+	chain.Scale = g.gctx.Zoom
+	down := chain.Downscale()
+	midlay := g.midlay
+	if down > 1 {
+		worldGeom.Scale(1/down, 1/down)
+		worldGeom.SetElement(0, 2, math.Round(worldGeom.Element(0, 2)))
+		worldGeom.SetElement(1, 2, math.Round(worldGeom.Element(1, 2)))
+		uiGeom.Scale(1/down, 1/down)
+		midlay = g.midlay.SubImage(image.Rect(0, 0, int(g.gctx.Width/down), int(g.gctx.Height/down))).(*ebiten.Image)
+	}
 
-	g.dctx.Target = g.midlay
+	g.dctx.Target = midlay
 	g.dctx.Op = op
+	g.dctx.Width = float64(midlay.Bounds().Dx())
+	g.dctx.Height = float64(midlay.Bounds().Dy())
 
 	g.midlay.Clear()
-	g.midlay.Fill(color.NRGBA{10, 10, 10, 255})
+	midlay.Fill(color.NRGBA{10, 10, 10, 255})
 
 	g.debugUI.Draw(&g.dctx)
 
 	// A bit terrible to merge like this, but oh wel..
 	startProfile("draw drawables")
 	referables := append(g.gctx.Place.referables, g.gctx.Referables...)
+	finished := false
 	for _, t := range referables.SortedDrawables() {
+		// UI sits still while the world moves under the camera, and isn't lit or rained on either.
+		if t.Priority() >= ables.PriorityUI {
+			if !finished {
+				g.finishWorld(referables, midlay, worldGeom)
+				finished = true
+			}
+			op.GeoM = uiGeom
+		} else {
+			op.GeoM = worldGeom
+		}
 		t.Draw(&g.dctx)
 	}
+	if !finished {
+		g.finishWorld(referables, midlay, worldGeom)
+	}
+	g.dctx.Flush()
 	endProfile("draw drawables")
 
+	// Everything is drawn to the place's post-processing chain, which hands us the screen itself if it has nothing to do.
+	frame := chain.Begin(screen)
+
 	op = &ebiten.DrawImageOptions{}
-	screen.DrawImage(g.midlay, op)
+	frame.DrawImage(midlay, op)
 
 	op.Blend = ebiten.BlendDestinationAtop
 
 	startProfile("draw overlays")
 	for _, t := range referables.Overlays() {
-		t.DrawTo(screen)
+		if _, ok := t.(WorldOverlay); ok {
+			continue
+		}
+		t.DrawTo(frame)
 	}
 	endProfile("draw overlays")
 
+	startProfile("post")
+	chain.End(screen, frame)
+	endProfile("post")
+
+	if g.gctx.Transition != nil {
+		g.gctx.Transition.Draw(screen)
+	}
+
 	// Print our debuggies
 	if debug {
 		for _, t := range referables.Debugables() {
-			ebitenutil.DebugPrintAt(g.debugUI.img, t.String(), int(t.X()*g.gctx.Zoom), int(t.Y()*g.gctx.Zoom))
+			x, y := g.gctx.Camera.WorldToScreen(&g.gctx, t.X(), t.Y())
+			ebitenutil.DebugPrintAt(g.debugUI.img, t.String(), int(x), int(y))
 		}
 		for i, p := range profiles {
 			ebitenutil.DebugPrintAt(g.debugUI.img, fmt.Sprintf("%03d %s", p.duration.Milliseconds(), p.name), 0, 30+i*10)
@@ -221,11 +363,98 @@ func (g *State) Draw(screen *ebiten.Image) {
 	}
 }
 
-// Layout is a thing, yo.
+// OnResume hides the cursor again and puts any item picked from the inventory screen in the player's hand.
+func (g *State) OnResume(data any) {
+	ebiten.SetCursorMode(ebiten.CursorModeHidden)
+	g.remap()
+	switch d := data.(type) {
+	case string:
+		g.holdItem(d)
+	case quitToTitle:
+		g.quitting = true
+	}
+}
+
+// remap rebinds our inputs, in case they were changed in the settings.
+func (g *State) remap() {
+	g.input.Remap(config.Keymap(gameBindings))
+	g.gctx.Camera.Remap()
+	g.gctx.Pointer.Remap()
+	if inv, ok := g.gctx.Referables.ByFirstTag("inventory").(*Inventory); ok {
+		inv.Remap()
+	}
+	if pl, ok := g.gctx.Referables.ByFirstTag("qi").(*Thinger); ok {
+		if pc, ok := pl.controller.(*PlayerController); ok {
+			pc.Remap()
+		}
+	}
+}
+
+// storage returns the player's storage.
+func (g *State) storage() ables.Storagable {
+	if pl, ok := g.gctx.Referables.ByFirstTag("qi").(*Thinger); ok {
+		return pl.Storagable
+	}
+	return nil
+}
+
+// holdItem puts the item with the given tag in the player's hand, as if it were clicked up out of the inventory.
+func (g *State) holdItem(tag string) {
+	if pl, ok := g.gctx.Referables.ByFirstTag("qi").(*Thinger); ok {
+		if pc, ok := pl.controller.(*PlayerController); ok && pl.HasItem(tag) {
+			pc.heldItem = tag
+			pc.dragging = false
+		}
+	}
+}
+
+// updateAudio keeps the music and ambience to the current place's, so traveling crossfades between them, and hears positional sounds from the player.
+func (g *State) updateAudio() {
+	if g.input.ActionIsJustPressed(InputMute) {
+		audio.SetMuted(!audio.Muted())
+	}
+	if t, ok := g.gctx.Referables.ByFirstTag("qi").(Drawable); ok {
+		audio.SetListener(t.X(), t.Y())
+	}
+	audio.PlayMusic(g.gctx.Place.music)
+	audio.PlayAmbience(g.gctx.Place.ambience)
+}
+
+// centerTransition keeps a travel transition's iris on the player, wherever they are on either side of it.
+func (g *State) centerTransition() {
+	t := g.gctx.Transition
+	if t == nil || g.gctx.Width == 0 || g.gctx.Height == 0 {
+		return
+	}
+	if pl, ok := g.gctx.Referables.ByFirstTag("qi").(Drawable); ok {
+		x, y := g.gctx.Camera.WorldToScreen(&g.gctx, pl.X(), pl.Y())
+		t.X = x / g.gctx.Width
+		t.Y = y / g.gctx.Height
+	}
+}
+
+// setZoom sets how many screen pixels make up an art pixel.
+func (g *State) setZoom(zoom float64) {
+	g.geom.Reset()
+	g.geom.Scale(zoom, zoom)
+	g.gctx.Zoom = zoom
+}
+
+// finishWorld applies any world overlays to the world drawn so far, in priority order, and then lights it all, so weather out in the dark stays dark.
+func (g *State) finishWorld(referables Referables, midlay *ebiten.Image, worldGeom ebiten.GeoM) {
+	g.dctx.Flush()
+	for _, t := range referables.SortedDrawables() {
+		if o, ok := t.(WorldOverlay); ok {
+			o.DrawTo(midlay)
+		}
+	}
+	g.gctx.Lighting.Draw(&g.gctx, midlay, worldGeom)
+}
+
+// Layout is a thing, yo. The zoom follows the canvas size, so the world always shows the same number of art pixels.
 func (g *State) Layout(ow, oh int) (int, int) {
-	ow = 1280
-	oh = 720
-	if g.dctx.Width != float64(ow) || g.dctx.Height != float64(oh) {
+	if g.gctx.Width != float64(ow) || g.gctx.Height != float64(oh) {
+		g.setZoom(float64(oh) / statemachine.LogicalHeight)
 		g.dctx.Width = float64(ow)
 		g.dctx.Height = float64(oh)
 		g.gctx.Width = float64(ow)
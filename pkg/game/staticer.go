@@ -2,11 +2,8 @@ package game
 
 import (
 	"fmt"
-	"image"
-	"math"
 	"math/rand/v2"
 
-	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/kettek/ehh24/pkg/game/ables"
 	"github.com/kettek/ehh24/pkg/game/context"
 )
@@ -20,6 +17,7 @@ type Staticer struct {
 	ables.Priorityable
 	originX float64
 	originY float64
+	opacity float32
 }
 
 // NewStaticer makes a staticer, wow.
@@ -31,6 +29,7 @@ func NewStaticer(name string) *Staticer {
 		Tagable:      ables.MakeTagable(name),
 		originX:      -0.5,
 		originY:      -1,
+		opacity:      1,
 	}
 }
 
@@ -41,31 +40,14 @@ func (t *Staticer) Update(ctx *ContextGame) []Change {
 
 // Draw draws the staticer to da screen.
 func (t *Staticer) Draw(ctx *context.Draw) {
-	scale := ctx.Op.GeoM.Element(0, 0)
-
-	const sliceDistance = 1.5
-	sliceDistanceEnd := math.Max(1, sliceDistance*scale)
-
-	opts := &ebiten.DrawImageOptions{}
-	for i, slice := range t.frame.Slices {
-		for j := 0; j < int(sliceDistanceEnd); j++ {
-			opts.GeoM.Reset()
-			//opts.GeoM.Rotate(math.Pi / 30)
-			opts.GeoM.Translate(float64(t.stax.Stax.SliceWidth)*t.originX, float64(t.stax.Stax.SliceHeight)*t.originY)
-
-			opts.GeoM.Translate(t.X(), t.Y())
-
-			opts.GeoM.Translate(0, -sliceDistance*float64(i))
-
-			opts.GeoM.Concat(ctx.Op.GeoM)
-
-			opts.GeoM.Translate(0, float64(j))
-
-			opts.Blend = ctx.Op.Blend
-			sub := t.stax.EbiImage.SubImage(image.Rect(slice.X, slice.Y, slice.X+t.stax.Stax.SliceWidth, slice.Y+t.stax.Stax.SliceHeight)).(*ebiten.Image)
-			ctx.Target.DrawImage(sub, opts)
-		}
-	}
+	sprite := t.sprite()
+	sprite.X = t.X()
+	sprite.Y = t.Y()
+	sprite.OriginX = t.originX
+	sprite.OriginY = t.originY
+	sprite.SliceDistance = 1.5
+	sprite.ColorScale.ScaleAlpha(t.opacity)
+	ctx.DrawSprite(&sprite)
 }
 
 func (t *Staticer) String() string {
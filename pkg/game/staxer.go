@@ -1,6 +1,7 @@
 package game
 
 import (
+	"github.com/kettek/ehh24/pkg/render"
 	"github.com/kettek/ehh24/pkg/res"
 	"github.com/kettek/ehh24/pkg/stax"
 )
@@ -18,6 +19,7 @@ const (
 
 // Staxer is a contained state structure for rendering staxie files.
 type Staxer struct {
+	name       string
 	stax       res.StaxImage // hmm
 	stack      *stax.Stack
 	lastAnim   string
@@ -46,6 +48,7 @@ func NewStaxer(name string) Staxer {
 		panic("frame not found")
 	}
 	return Staxer{
+		name:      name,
 		stax:      st,
 		stack:     stack,
 		animation: animation,
@@ -64,6 +67,35 @@ func (s *Staxer) Stack(name string) {
 	s.Animation(stack.Animations[0].Name)
 }
 
+// reloadStax re-fetches the stax if it has the given name, keeping the current stack and animation if they still exist.
+func (s *Staxer) reloadStax(name string) {
+	if s.name != name {
+		return
+	}
+	st, err := res.GetStax(name)
+	if err != nil || len(st.Stax.Stacks) == 0 || len(st.Stax.Stacks[0].Animations) == 0 {
+		return
+	}
+	stack := s.stack.Name
+	animation := s.animation.Name
+
+	s.stax = st
+	s.stack = &st.Stax.Stacks[0]
+	s.lastAnim = "ieee"
+	s.Animation(s.stack.Animations[0].Name)
+	s.setStackAndAnimation(stack, animation)
+}
+
+// setStackAndAnimation sets the stack and animation if they are provided and exist.
+func (s *Staxer) setStackAndAnimation(stack, animation string) {
+	if stack != "" && s.stax.Stax.Stack(stack) != nil {
+		s.Stack(stack)
+	}
+	if animation != "" && s.stack.Animation(animation) != nil {
+		s.Animation(animation)
+	}
+}
+
 // Animation sets the staxer's animation to the given string. Panics if no string exists.
 func (s *Staxer) Animation(name string) {
 	if s.lastAnim == name {
@@ -92,3 +124,13 @@ func (s *Staxer) Update() {
 		s.frame = s.animation.Frame(s.frameIndex)
 	}
 }
+
+// sprite returns a sprite of the current frame for the rest to be filled in.
+func (s *Staxer) sprite() render.Sprite {
+	return render.Sprite{
+		Image:       s.stax.EbiImage,
+		SliceWidth:  s.stax.Stax.SliceWidth,
+		SliceHeight: s.stax.Stax.SliceHeight,
+		Frame:       s.frame,
+	}
+}
@@ -2,7 +2,6 @@ package game
 
 import (
 	"fmt"
-	"image"
 	"image/color"
 	"math"
 
@@ -78,9 +77,11 @@ func (t *Thinger) Draw(ctx *context.Draw) {
 	t.draw(ctx)
 
 	if debug {
+		ctx.Flush()
 		cx := t.X() + float64(t.stax.Stax.SliceWidth)*t.centerX
 		cy := t.Y() + float64(t.stax.Stax.SliceHeight)*t.centerY
-		ebitenutil.DrawCircle(ctx.Target, cx*ctx.Op.GeoM.Element(0, 0), cy*ctx.Op.GeoM.Element(0, 0), 2, color.NRGBA{255, 0, 0, 255})
+		cx, cy = ctx.Op.GeoM.Apply(cx, cy)
+		ebitenutil.DrawCircle(ctx.Target, cx, cy, 2, color.NRGBA{255, 0, 0, 255})
 	}
 
 	if t.monologue != "" {
@@ -92,53 +93,42 @@ func (t *Thinger) Draw(ctx *context.Draw) {
 }
 
 func (t *Thinger) draw(ctx *context.Draw) {
-	opts := &ebiten.DrawImageOptions{}
-
-	for _, i := range t.sortedSlices() {
-		slice := t.frame.Slices[i]
-		opts.GeoM.Reset()
-
-		opts.GeoM.Translate(-float64(t.stax.Stax.SliceWidth)*t.centerX, -float64(t.stax.Stax.SliceHeight)*t.centerY)
-		opts.GeoM.Rotate(t.rotation)
-		opts.GeoM.Translate(float64(t.stax.Stax.SliceWidth)*t.centerX, float64(t.stax.Stax.SliceHeight)*t.centerY)
-
-		opts.GeoM.Translate(float64(t.stax.Stax.SliceWidth)*t.originX, float64(t.stax.Stax.SliceHeight)*t.originY)
-
-		if t.faceLeft {
-			opts.GeoM.Scale(-1, 1)
-		}
+	sprite := t.sprite()
+	sprite.Order = t.sortedSlices()
+	sprite.X = t.X()
+	sprite.Y = t.Y()
+	sprite.OriginX = t.originX
+	sprite.OriginY = t.originY
+	sprite.CenterX = t.centerX
+	sprite.CenterY = t.centerY
+	sprite.Rotation = t.rotation
+	sprite.FlipX = t.faceLeft
+	sprite.SliceOffset = t.sliceOffset
+	ctx.DrawSprite(&sprite)
+}
 
-		if i == Eyes {
-			lookX := math.Max(-1, math.Min(1, t.lookX))
-			lookY := math.Max(-1, math.Min(1, t.lookY))
-			opts.GeoM.Translate(lookX, lookY)
-		} else if i == Head {
-			lookY := math.Max(-1, math.Min(1, t.lookY))
-			opts.GeoM.Translate(0, lookY/4)
-		} else if i == Heart {
-			opts.GeoM.Translate(0, math.Sin(float64(t.ticker)/50)*0.8)
-		}
-		if t.walking {
-			if i == FrontLeg {
-				opts.GeoM.Translate(0, math.Sin(float64(t.walkTicker)/10)*0.4)
-			} else if i == BackLeg {
-				opts.GeoM.Translate(0, -math.Sin(float64(t.walkTicker)/10)*0.4)
-			} else if i == FrontArm {
-				opts.GeoM.Translate(0, math.Sin(float64(t.walkTicker)/10)*0.2)
-			} else if i == BackArm {
-				opts.GeoM.Translate(0, -math.Sin(float64(t.walkTicker)/10)*0.2)
-			}
+// sliceOffset wiggles our bits and bobs.
+func (t *Thinger) sliceOffset(i int) (x, y float64) {
+	if i == Eyes {
+		x = math.Max(-1, math.Min(1, t.lookX))
+		y = math.Max(-1, math.Min(1, t.lookY))
+	} else if i == Head {
+		y = math.Max(-1, math.Min(1, t.lookY)) / 4
+	} else if i == Heart {
+		y = math.Sin(float64(t.ticker)/50) * 0.8
+	}
+	if t.walking {
+		if i == FrontLeg {
+			y += math.Sin(float64(t.walkTicker)/10) * 0.4
+		} else if i == BackLeg {
+			y -= math.Sin(float64(t.walkTicker)/10) * 0.4
+		} else if i == FrontArm {
+			y += math.Sin(float64(t.walkTicker)/10) * 0.2
+		} else if i == BackArm {
+			y -= math.Sin(float64(t.walkTicker)/10) * 0.2
 		}
-
-		opts.GeoM.Translate(t.X(), t.Y())
-
-		opts.Filter = ctx.Op.Filter
-		opts.Blend = ctx.Op.Blend
-		opts.GeoM.Concat(ctx.Op.GeoM)
-
-		sub := t.stax.EbiImage.SubImage(image.Rect(slice.X, slice.Y, slice.X+t.stax.Stax.SliceWidth, slice.Y+t.stax.Stax.SliceHeight)).(*ebiten.Image)
-		ctx.Target.DrawImage(sub, opts)
 	}
+	return x, y
 }
 
 func (t *Thinger) String() string {
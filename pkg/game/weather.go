@@ -0,0 +1,267 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"math/rand/v2"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/kettek/ehh24/pkg/game/ables"
+	"github.com/kettek/ehh24/pkg/game/context"
+	"github.com/kettek/ehh24/pkg/res"
+)
+
+// weatherFade is how much a kind of weather's intensity changes per tick, so travel between indoors and outdoors eases in and out.
+const weatherFade = 0.005
+
+// weatherParticles is how many particles each kind of weather has at full intensity.
+var weatherParticles = map[res.WeatherKind]int{
+	res.WeatherKindSnow: 300,
+	res.WeatherKindRain: 400,
+	res.WeatherKindDust: 150,
+	res.WeatherKindFog:  12,
+}
+
+// WeatherOverlay draws the current place's weather over the world. Every kind of weather fades separately, so one can fade out while another fades in.
+type WeatherOverlay struct {
+	ables.IDable
+	ables.Priorityable
+	ables.Tagable
+	ables.Positionable
+	layers  []*weatherLayer
+	wind    float64 // Wind eased towards the place's.
+	gust    float64
+	gustDir float64
+	zoom    float64
+	width   float64
+	height  float64
+	flake   *ebiten.Image
+	blob    *ebiten.Image
+	canvas  *ebiten.Image // For drawing to smaller images.
+}
+
+// weatherLayer is the particles of one kind of weather.
+type weatherLayer struct {
+	kind      res.WeatherKind
+	intensity float64
+	particles []weatherParticle
+}
+
+// weatherParticle is a snowflake, raindrop, mote, or fog bank.
+type weatherParticle struct {
+	x, y, z float64
+}
+
+// NewWeatherOverlay creates a new weather overlay.
+func NewWeatherOverlay(width, height int) *WeatherOverlay {
+	d := &WeatherOverlay{
+		IDable:  ables.NextIDable(),
+		gustDir: 0.001,
+		zoom:    1,
+		flake:   softCircle(8, 1),
+		blob:    softCircle(64, 0),
+	}
+	for _, kind := range res.WeatherKinds {
+		if n, ok := weatherParticles[kind]; ok {
+			d.layers = append(d.layers, &weatherLayer{
+				kind:      kind,
+				particles: make([]weatherParticle, n),
+			})
+		}
+	}
+	d.Resize(width, height)
+	return d
+}
+
+// softCircle makes a white circle, fading from solid at hardness of its radius out to nothing at its edge.
+func softCircle(size int, hardness float64) *ebiten.Image {
+	img := ebiten.NewImage(size, size)
+	pix := make([]byte, size*size*4)
+	r := float64(size) / 2
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			d := math.Hypot(float64(x)+0.5-r, float64(y)+0.5-r) / r
+			a := 0.0
+			if d < hardness {
+				a = 1
+			} else if d < 1 {
+				a = (1 - d) / (1 - hardness)
+			}
+			v := byte(a * 255)
+			i := (y*size + x) * 4
+			pix[i], pix[i+1], pix[i+2], pix[i+3] = v, v, v, v
+		}
+	}
+	img.WritePixels(pix)
+	return img
+}
+
+// Update fades each kind of weather towards the place's and moves their particles along.
+func (d *WeatherOverlay) Update(ctx *ContextGame) []Change {
+	weather := ctx.Place.weather
+
+	d.wind += (weather.Wind - d.wind) * 0.01
+	d.gust += d.gustDir
+	if d.gust > 1 {
+		d.gustDir = -0.001
+	} else if d.gust < -1 {
+		d.gustDir = 0.001
+	}
+	wind := (d.wind + d.gust*0.5) * d.zoom
+
+	for _, l := range d.layers {
+		target := 0.0
+		if l.kind == weather.Kind {
+			target = weather.Intensity
+		}
+		if l.intensity < target {
+			l.intensity = math.Min(target, l.intensity+weatherFade)
+		} else if l.intensity > target {
+			l.intensity = math.Max(target, l.intensity-weatherFade)
+		}
+		if l.intensity <= 0 {
+			continue
+		}
+		for i := range l.particles {
+			d.move(l.kind, &l.particles[i], wind)
+		}
+	}
+	return nil
+}
+
+// move moves a particle along, respawning it once it leaves the view or melts away.
+func (d *WeatherOverlay) move(kind res.WeatherKind, p *weatherParticle, wind float64) {
+	switch kind {
+	case res.WeatherKindSnow:
+		p.x += wind
+		p.z -= 0.01
+		p.y += (p.z/4 + 0.1) * d.zoom
+		if p.y > d.height || p.z <= 0 {
+			p.x = rand.Float64() * d.width
+			p.y = rand.Float64() * d.height
+			p.z = 2
+		}
+	case res.WeatherKindRain:
+		p.x += wind * 2
+		p.y += (4 + p.z*2) * d.zoom
+		if p.y > d.height {
+			p.x = rand.Float64() * d.width
+			p.y = -rand.Float64() * d.height / 4
+		}
+	case res.WeatherKindDust:
+		p.x += (wind + 0.3*p.z) * d.zoom
+		p.y += math.Sin(p.x/(20*d.zoom)+p.z*10) * 0.2 * d.zoom
+	case res.WeatherKindFog:
+		p.x += (wind*0.2 + 0.05*p.z) * d.zoom
+	}
+	// Wrap sideways-drifting particles around.
+	if p.x > d.width+d.size(kind, p) {
+		p.x = -d.size(kind, p)
+	} else if p.x < -d.size(kind, p) {
+		p.x = d.width + d.size(kind, p)
+	}
+}
+
+// size returns the drawn size of a particle.
+func (d *WeatherOverlay) size(kind res.WeatherKind, p *weatherParticle) float64 {
+	switch kind {
+	case res.WeatherKindFog:
+		return float64(d.blob.Bounds().Dx()) * (2 + p.z) * d.zoom
+	case res.WeatherKindSnow:
+		return float64(d.flake.Bounds().Dx()) * p.z
+	}
+	return 2 * d.zoom
+}
+
+// Draw just notes the zoom in our own pixels, as the weather is drawn as an overlay.
+func (d *WeatherOverlay) Draw(ctx *context.Draw) {
+	d.zoom = ctx.Op.GeoM.Element(0, 0) * d.width / ctx.Width
+}
+
+// Resize resizes the weather overlay, scattering the particles over the new size.
+func (d *WeatherOverlay) Resize(width, height int) {
+	d.width = float64(width)
+	d.height = float64(height)
+	for _, l := range d.layers {
+		for i := range l.particles {
+			l.particles[i] = weatherParticle{
+				x: rand.Float64() * d.width,
+				y: rand.Float64() * d.height,
+				z: rand.Float64() * 2,
+			}
+			if l.kind == res.WeatherKindRain || l.kind == res.WeatherKindDust || l.kind == res.WeatherKindFog {
+				l.particles[i].z = rand.Float64()
+			}
+		}
+	}
+}
+
+// DrawTo draws the weather to an image. Images smaller than us, as when the world is drawn small to be pixelated, get it shrunk down to fit.
+func (d *WeatherOverlay) DrawTo(img *ebiten.Image) {
+	if img.Bounds().Dx() >= int(d.width) {
+		d.drawParticles(img)
+		return
+	}
+	if d.canvas == nil || d.canvas.Bounds().Dx() != int(d.width) || d.canvas.Bounds().Dy() != int(d.height) {
+		d.canvas = ebiten.NewImage(int(d.width), int(d.height))
+	}
+	d.canvas.Clear()
+	d.drawParticles(d.canvas)
+	op := &ebiten.DrawImageOptions{}
+	scale := float64(img.Bounds().Dx()) / d.width
+	op.GeoM.Scale(scale, scale)
+	img.DrawImage(d.canvas, op)
+}
+
+// drawParticles draws the weather's particles to an image our size.
+func (d *WeatherOverlay) drawParticles(img *ebiten.Image) {
+	for _, l := range d.layers {
+		if l.intensity <= 0 {
+			continue
+		}
+		count := int(float64(len(l.particles)) * math.Min(1, l.intensity))
+		alpha := float32(math.Min(1, l.intensity*2))
+		switch l.kind {
+		case res.WeatherKindSnow:
+			for _, p := range l.particles[:count] {
+				op := &ebiten.DrawImageOptions{}
+				op.GeoM.Scale(p.z, p.z)
+				op.GeoM.Translate(p.x, p.y)
+				op.ColorScale.ScaleAlpha((2.0 - float32(p.z)) * alpha)
+				img.DrawImage(d.flake, op)
+			}
+		case res.WeatherKindRain:
+			for _, p := range l.particles[:count] {
+				length := (4 + p.z*4) * d.zoom
+				clr := color.NRGBA{170, 190, 230, uint8(120 * alpha)}
+				vector.StrokeLine(img, float32(p.x), float32(p.y), float32(p.x+d.wind*d.zoom), float32(p.y+length), float32(d.zoom/2), clr, false)
+			}
+		case res.WeatherKindDust:
+			for _, p := range l.particles[:count] {
+				clr := color.NRGBA{200, 170, 120, uint8((80 + 100*p.z) * float64(alpha))}
+				vector.DrawFilledRect(img, float32(p.x), float32(p.y), float32(d.zoom), float32(d.zoom), clr, false)
+			}
+		case res.WeatherKindFog:
+			vector.DrawFilledRect(img, 0, 0, float32(d.width), float32(d.height), color.NRGBA{150, 155, 160, uint8(50 * l.intensity)}, false)
+			for _, p := range l.particles[:count] {
+				scale := (2 + p.z) * d.zoom
+				op := &ebiten.DrawImageOptions{}
+				op.GeoM.Scale(scale, scale)
+				op.GeoM.Translate(p.x-float64(d.blob.Bounds().Dx())*scale/2, p.y-float64(d.blob.Bounds().Dy())*scale/2)
+				op.ColorScale.Scale(0.6, 0.62, 0.65, 1)
+				op.ColorScale.ScaleAlpha(float32(0.35 * l.intensity))
+				img.DrawImage(d.blob, op)
+			}
+		}
+	}
+}
+
+// WorldOverlay marks the weather as part of the world, so the UI stays clear of it.
+func (d *WeatherOverlay) WorldOverlay() {}
+
+// String returns a string representation of the weather overlay.
+func (d *WeatherOverlay) String() string {
+	return fmt.Sprintf("%d:%s:%d", d.ID(), d.Tag(), d.Priority())
+}
@@ -38,7 +38,8 @@ func (s *Scene1) Draw(ctx context.Draw) {
 	if s.dropY < s.targetY {
 		op.ColorScale.ScaleAlpha(float32(s.dropY+50) / float32(s.targetY))
 		drop := res.Images["drop"]
-		op.GeoM.Translate(220, s.dropY)
+		w, _ := ctx.Size()
+		op.GeoM.Translate(w/2, s.dropY)
 		op.GeoM.Translate(-float64(drop.Bounds().Dx())/2, -float64(drop.Bounds().Dy())/2)
 		op.GeoM.Concat(ctx.Op.GeoM)
 		ctx.Target.DrawImage(drop, op)
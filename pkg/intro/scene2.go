@@ -49,8 +49,9 @@ func (s *Scene2) Draw(ctx context.Draw) {
 
 	clr := color.RGBA{a, a, a, a}
 
-	op.GeoM.Translate(220, 100)
-	op.GeoM.Scale(3, 3)
+	w, _ := ctx.Size()
+	op.GeoM.Translate(w/2, 100)
+	op.GeoM.Concat(ctx.Op.GeoM)
 
 	ctx.Text("ミル・・・ネ", op.GeoM, clr)
 }
@@ -16,8 +16,6 @@ type Scene interface {
 // State be our intro.
 type State struct {
 	scene       Scene
-	fadeIn      int
-	fadeOut     int
 	width       int
 	height      int
 	drawContext context.Draw
@@ -25,10 +23,7 @@ type State struct {
 
 // NewState creates a new state.
 func NewState() *State {
-	return &State{
-		fadeIn:  100,
-		fadeOut: 100,
-	}
+	return &State{}
 }
 
 // Init is called when the state is to be first entered.
@@ -50,12 +45,14 @@ func (s *State) Update() statemachine.State {
 func (s *State) Draw(screen *ebiten.Image) {
 	s.drawContext.Target = screen
 	s.drawContext.Op = &ebiten.DrawImageOptions{}
-	s.drawContext.Op.GeoM.Scale(3, 3)
+	s.drawContext.Op.GeoM.Scale(statemachine.Zoom(), statemachine.Zoom())
 	s.scene.Draw(s.drawContext)
 }
 
 // Layout does a layout.
 func (s *State) Layout(ow, oh int) (int, int) {
 	s.width, s.height = ow, oh
+	s.drawContext.Width = float64(ow)
+	s.drawContext.Height = float64(oh)
 	return ow, oh
 }
@@ -0,0 +1,163 @@
+// Package lang changes how the game's text is written. Everything is written in katakana, which can be shown as is, as hiragana, or romanized.
+package lang
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Language is a way of writing the game's text.
+type Language int
+
+// Our languages.
+const (
+	LanguageKatakana Language = iota
+	LanguageHiragana
+	LanguageRomaji
+)
+
+// Languages is every language, in order.
+var Languages = []Language{LanguageKatakana, LanguageHiragana, LanguageRomaji}
+
+// Current is the language text is shown in.
+var Current = LanguageKatakana
+
+// String returns the string representation of a Language.
+func (l Language) String() string {
+	switch l {
+	case LanguageKatakana:
+		return "Katakana"
+	case LanguageHiragana:
+		return "Hiragana"
+	case LanguageRomaji:
+		return "Romaji"
+	}
+	return "Unknown"
+}
+
+// MarshalText writes the language by name.
+func (l Language) MarshalText() ([]byte, error) {
+	for _, language := range Languages {
+		if language == l {
+			return []byte(l.String()), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown language %d", l)
+}
+
+// UnmarshalText reads the language by name.
+func (l *Language) UnmarshalText(text []byte) error {
+	for _, language := range Languages {
+		if language.String() == string(text) {
+			*l = language
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown language %q", text)
+}
+
+// Kana offsets, as hiragana and katakana are laid out the same in unicode.
+const (
+	hiraganaStart = 'ぁ'
+	hiraganaEnd   = 'ゖ'
+	katakanaStart = 'ァ'
+	katakanaEnd   = 'ヶ'
+	kanaOffset    = katakanaStart - hiraganaStart
+)
+
+// Text writes text in the current language. Anything that isn't kana is left alone.
+func Text(s string) string {
+	switch Current {
+	case LanguageHiragana:
+		return strings.Map(func(r rune) rune {
+			if r >= katakanaStart && r <= katakanaEnd {
+				return r - kanaOffset
+			}
+			return r
+		}, s)
+	case LanguageRomaji:
+		return romanize(s)
+	}
+	return s
+}
+
+// romaji is the romanization of each katakana.
+var romaji = map[rune]string{
+	'ア': "a", 'イ': "i", 'ウ': "u", 'エ': "e", 'オ': "o",
+	'カ': "ka", 'キ': "ki", 'ク': "ku", 'ケ': "ke", 'コ': "ko",
+	'ガ': "ga", 'ギ': "gi", 'グ': "gu", 'ゲ': "ge", 'ゴ': "go",
+	'サ': "sa", 'シ': "shi", 'ス': "su", 'セ': "se", 'ソ': "so",
+	'ザ': "za", 'ジ': "ji", 'ズ': "zu", 'ゼ': "ze", 'ゾ': "zo",
+	'タ': "ta", 'チ': "chi", 'ツ': "tsu", 'テ': "te", 'ト': "to",
+	'ダ': "da", 'ヂ': "ji", 'ヅ': "zu", 'デ': "de", 'ド': "do",
+	'ナ': "na", 'ニ': "ni", 'ヌ': "nu", 'ネ': "ne", 'ノ': "no",
+	'ハ': "ha", 'ヒ': "hi", 'フ': "fu", 'ヘ': "he", 'ホ': "ho",
+	'バ': "ba", 'ビ': "bi", 'ブ': "bu", 'ベ': "be", 'ボ': "bo",
+	'パ': "pa", 'ピ': "pi", 'プ': "pu", 'ペ': "pe", 'ポ': "po",
+	'マ': "ma", 'ミ': "mi", 'ム': "mu", 'メ': "me", 'モ': "mo",
+	'ヤ': "ya", 'ユ': "yu", 'ヨ': "yo",
+	'ラ': "ra", 'リ': "ri", 'ル': "ru", 'レ': "re", 'ロ': "ro",
+	'ワ': "wa", 'ヲ': "wo", 'ン': "n", 'ヴ': "vu",
+	'・': ".", '！': "!", '？': "?", '、': ",", '。': ".", '　': " ",
+}
+
+// small are the small kana that change the sound before them, by the vowel they end it with.
+var small = map[rune]string{
+	'ャ': "ya", 'ュ': "yu", 'ョ': "yo",
+	'ァ': "a", 'ィ': "i", 'ゥ': "u", 'ェ': "e", 'ォ': "o",
+}
+
+// romanize writes kana in romaji, such as "キャット" as "kyatto".
+func romanize(s string) string {
+	var b strings.Builder
+	double := false // A small tsu doubles the next consonant.
+	var last string
+	for _, r := range s {
+		if r >= hiraganaStart && r <= hiraganaEnd {
+			r += kanaOffset
+		}
+		if r == 'ッ' {
+			double = true
+			continue
+		}
+		if r == 'ー' {
+			// Long vowels just repeat the vowel.
+			if last != "" {
+				last = last[len(last)-1:]
+				b.WriteString(last)
+			}
+			continue
+		}
+		if sound, ok := small[r]; ok && last != "" {
+			// Swap the vowel of the last sound, such as "ki" and "ya" to "kya", or "shi" and "ya" to "sha".
+			prev := strings.TrimSuffix(last, last[len(last)-1:])
+			if sound[0] == 'y' && (strings.HasSuffix(prev, "sh") || strings.HasSuffix(prev, "ch") || prev == "j") {
+				sound = sound[1:]
+			}
+			written := b.String()
+			b.Reset()
+			b.WriteString(strings.TrimSuffix(written, last))
+			last = prev + sound
+			b.WriteString(last)
+			continue
+		}
+		sound, ok := romaji[r]
+		if !ok {
+			double = false
+			last = ""
+			b.WriteRune(r)
+			continue
+		}
+		if double {
+			if strings.HasPrefix(sound, "ch") {
+				b.WriteByte('t')
+			} else if sound != "n" && !strings.ContainsAny(sound[:1], "aiueo") {
+				b.WriteByte(sound[0])
+			}
+			double = false
+		}
+		last = sound
+		b.WriteString(sound)
+	}
+	return b.String()
+}
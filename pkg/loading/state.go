@@ -0,0 +1,59 @@
+package loading
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/kettek/ehh24/pkg/res"
+	"github.com/kettek/ehh24/pkg/statemachine"
+)
+
+// State shows the progress of a res.Loader, moving on to the next state once it is done.
+type State struct {
+	loader *res.Loader
+	next   func() statemachine.State
+	width  int
+	height int
+}
+
+// NewState creates a new loading state. next is called to create the state to move to once loading is done.
+func NewState(loader *res.Loader, next func() statemachine.State) *State {
+	return &State{
+		loader: loader,
+		next:   next,
+	}
+}
+
+// Init is called when the state is to be first entered.
+func (s *State) Init() {
+}
+
+// Update updates the loader and moves on when it is done.
+func (s *State) Update() statemachine.State {
+	s.loader.Update()
+	if s.loader.Done() {
+		if err := s.loader.Err(); err != nil {
+			panic(err)
+		}
+		return s.next()
+	}
+	return nil
+}
+
+// Draw draws a progress bar.
+func (s *State) Draw(screen *ebiten.Image) {
+	const barHeight = 4
+	w := float32(s.width) / 3
+	x := float32(s.width)/2 - w/2
+	y := float32(s.height)/2 - barHeight/2
+
+	vector.StrokeRect(screen, x-2, y-2, w+4, barHeight+4, 1, color.NRGBA{139, 98, 16, 255}, false)
+	vector.DrawFilledRect(screen, x, y, w*float32(s.loader.Progress()), barHeight, color.NRGBA{16, 98, 139, 255}, false)
+}
+
+// Layout does a layout.
+func (s *State) Layout(ow, oh int) (int, int) {
+	s.width, s.height = ow, oh
+	return ow, oh
+}
@@ -0,0 +1,219 @@
+// Package menu is a list of choices over whatever is below it, picked with keys, gamepad, or mouse.
+package menu
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/kettek/ehh24/pkg/game/context"
+	"github.com/kettek/ehh24/pkg/statemachine"
+	input "github.com/quasilyte/ebitengine-input"
+)
+
+// Our inputs for menus.
+const (
+	InputUp input.Action = iota
+	InputDown
+	InputLeft
+	InputRight
+	InputAccept
+	InputBack
+	InputClick
+)
+
+// Layout of the menu, in art pixels.
+const (
+	lineHeight = 14
+	itemWidth  = 140
+	titleGap   = 24
+)
+
+// Colors of the menu.
+var (
+	colorTitle    = color.NRGBA{139, 98, 16, 255}
+	colorItem     = color.NRGBA{200, 200, 200, 255}
+	colorSelected = color.NRGBA{255, 220, 140, 255}
+	colorBackdrop = color.NRGBA{0, 0, 0, 220}
+)
+
+// Item is a choice in a menu.
+type Item struct {
+	Label    string
+	Activate func() statemachine.State // Called when chosen. Whatever it returns is handed back from Update.
+	Adjust   func(dir int)             // Called with -1 or 1 for left and right on the item, for settings and the like.
+}
+
+// Menu is a titled list of items.
+type Menu struct {
+	Title    string
+	Items    []Item
+	Back     func() statemachine.State // Called when backing out of the menu.
+	Selected int
+	Backdrop bool // Darken whatever is under the menu.
+	insys    input.System
+	input    *input.Handler
+	mouseX   int
+	mouseY   int
+	dctx     context.Draw
+}
+
+// New makes a menu.
+func New(title string, items ...Item) *Menu {
+	m := &Menu{
+		Title:    title,
+		Items:    items,
+		Backdrop: true,
+	}
+	m.insys.Init(input.SystemConfig{
+		DevicesEnabled: input.AnyDevice,
+	})
+	m.input = m.insys.NewHandler(0, input.Keymap{
+		InputUp:     {input.KeyUp, input.KeyW, input.KeyGamepadUp, input.KeyGamepadLStickUp},
+		InputDown:   {input.KeyDown, input.KeyS, input.KeyGamepadDown, input.KeyGamepadLStickDown},
+		InputLeft:   {input.KeyLeft, input.KeyA, input.KeyGamepadLeft, input.KeyGamepadLStickLeft},
+		InputRight:  {input.KeyRight, input.KeyD, input.KeyGamepadRight, input.KeyGamepadLStickRight},
+		InputAccept: {input.KeyEnter, input.KeySpace, input.KeyGamepadA},
+		InputBack:   {input.KeyEscape, input.KeyBackspace, input.KeyGamepadB},
+		InputClick:  {input.KeyMouseLeft, input.KeyTouchTap},
+	})
+	m.mouseX, m.mouseY = statemachine.CursorPosition()
+	return m
+}
+
+// Update moves the selection and activates items, returning whatever state they ask for.
+func (m *Menu) Update() statemachine.State {
+	m.insys.Update()
+	if statemachine.Transitioning() {
+		return nil
+	}
+
+	// The mouse picks whatever it moves over, but doesn't fight the keys otherwise.
+	mx, my := statemachine.CursorPosition()
+	zoom := statemachine.Zoom()
+	hovered := m.itemAt(float64(mx)/zoom, float64(my)/zoom)
+	if mx != m.mouseX || my != m.mouseY {
+		m.mouseX, m.mouseY = mx, my
+		if hovered >= 0 {
+			m.Selected = hovered
+		}
+	}
+
+	if m.input.ActionIsJustPressed(InputBack) && m.Back != nil {
+		return m.Back()
+	}
+	if len(m.Items) == 0 {
+		return nil
+	}
+	m.Selected = min(max(m.Selected, 0), len(m.Items)-1)
+	item := m.Items[m.Selected]
+
+	// Clicks and taps are checked where they happened, as taps don't move the cursor.
+	clicked, clickX := -1, 0.0
+	if info, ok := m.input.JustPressedActionInfo(InputClick); ok {
+		x, y := statemachine.ToCanvas(info.Pos.X, info.Pos.Y)
+		clickX = x / zoom
+		clicked = m.itemAt(clickX, y/zoom)
+	}
+
+	switch {
+	case m.input.ActionIsJustPressed(InputUp):
+		m.Selected = (m.Selected + len(m.Items) - 1) % len(m.Items)
+	case m.input.ActionIsJustPressed(InputDown):
+		m.Selected = (m.Selected + 1) % len(m.Items)
+	case m.input.ActionIsJustPressed(InputLeft):
+		if item.Adjust != nil {
+			item.Adjust(-1)
+		}
+	case m.input.ActionIsJustPressed(InputRight):
+		if item.Adjust != nil {
+			item.Adjust(1)
+		}
+	case m.input.ActionIsJustPressed(InputAccept):
+		if item.Activate != nil {
+			return item.Activate()
+		}
+		if item.Adjust != nil {
+			item.Adjust(1)
+		}
+	case clicked >= 0:
+		m.Selected = clicked
+		item = m.Items[clicked]
+		if item.Activate != nil {
+			return item.Activate()
+		}
+		// Clicking the left half of an adjustable item goes down, and the right half up.
+		if item.Adjust != nil {
+			w, _ := m.size()
+			if clickX < w/2 {
+				item.Adjust(-1)
+			} else {
+				item.Adjust(1)
+			}
+		}
+	}
+	return nil
+}
+
+// size returns the size of the canvas in art pixels.
+func (m *Menu) size() (float64, float64) {
+	w, h := statemachine.Size()
+	zoom := statemachine.Zoom()
+	return float64(w) / zoom, float64(h) / zoom
+}
+
+// top returns where the first item is, in art pixels.
+func (m *Menu) top() float64 {
+	_, h := m.size()
+	return h/2 - float64(len(m.Items)*lineHeight)/2 + titleGap/2
+}
+
+// itemAt returns the index of the item at the position in art pixels, or -1.
+func (m *Menu) itemAt(x, y float64) int {
+	w, _ := m.size()
+	if x < w/2-itemWidth/2 || x > w/2+itemWidth/2 {
+		return -1
+	}
+	i := int((y - m.top()) / lineHeight)
+	if y < m.top() || i >= len(m.Items) {
+		return -1
+	}
+	return i
+}
+
+// Draw draws the menu.
+func (m *Menu) Draw(screen *ebiten.Image) {
+	if m.Backdrop {
+		vector.DrawFilledRect(screen, 0, 0, float32(screen.Bounds().Dx()), float32(screen.Bounds().Dy()), colorBackdrop, false)
+	}
+
+	zoom := statemachine.Zoom()
+	m.dctx.Target = screen
+	m.dctx.Width = float64(screen.Bounds().Dx())
+	m.dctx.Height = float64(screen.Bounds().Dy())
+	m.dctx.Op = &ebiten.DrawImageOptions{}
+	m.dctx.Op.GeoM.Scale(zoom, zoom)
+
+	w, _ := m.size()
+	top := m.top()
+
+	geom := ebiten.GeoM{}
+	geom.Translate(w/2, top-titleGap)
+	geom.Concat(m.dctx.Op.GeoM)
+	m.dctx.Text(m.Title, geom, colorTitle)
+
+	for i, item := range m.Items {
+		label := item.Label
+		clr := colorItem
+		if i == m.Selected {
+			clr = colorSelected
+			if item.Adjust != nil {
+				label = "< " + label + " >"
+			}
+		}
+		geom := ebiten.GeoM{}
+		geom.Translate(w/2, top+float64(i*lineHeight))
+		geom.Concat(m.dctx.Op.GeoM)
+		m.dctx.Text(label, geom, clr)
+	}
+}
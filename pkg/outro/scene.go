@@ -50,11 +50,15 @@ func (s *Scene1) Draw(ctx context.Draw) {
 
 	clr := color.RGBA{a, a, a, a}
 
-	op.GeoM.Translate(220, 100)
-	op.GeoM.Scale(3, 3)
+	w, _ := ctx.Size()
+	op.GeoM.Translate(w/2, 100)
+	op.GeoM.Concat(ctx.Op.GeoM)
 
 	ctx.Text("アリガトウゴザイマス！", op.GeoM, clr)
 
-	op.GeoM.Translate(0, 80)
-	ctx.Target.DrawImage(res.Images["thanks"], op)
+	thanks := res.Images["thanks"]
+	op.GeoM.Reset()
+	op.GeoM.Translate(w/2-float64(thanks.Bounds().Dx())/2, 126)
+	op.GeoM.Concat(ctx.Op.GeoM)
+	ctx.Target.DrawImage(thanks, op)
 }
@@ -12,11 +12,12 @@ type Scene interface {
 	Draw(ctx context.Draw)
 }
 
-// State be our intro.
+// Finished is what the outro pops with once it is over, so the game it came from isn't continued.
+type Finished struct{}
+
+// State be our outro.
 type State struct {
 	scene       Scene
-	fadeIn      int
-	fadeOut     int
 	width       int
 	height      int
 	drawContext context.Draw
@@ -24,10 +25,7 @@ type State struct {
 
 // NewState creates a new state.
 func NewState() *State {
-	return &State{
-		fadeIn:  100,
-		fadeOut: 100,
-	}
+	return &State{}
 }
 
 // Init is called when the state is to be first entered.
@@ -38,7 +36,13 @@ func (s *State) Init() {
 
 // Update updates the state.
 func (s *State) Update() statemachine.State {
+	if s.scene == nil {
+		return nil
+	}
 	s.scene = s.scene.Update()
+	if s.scene == nil {
+		return statemachine.WithTransition(statemachine.Pop(Finished{}), statemachine.DefaultTransition())
+	}
 	return nil
 }
 
@@ -46,12 +50,16 @@ func (s *State) Update() statemachine.State {
 func (s *State) Draw(screen *ebiten.Image) {
 	s.drawContext.Target = screen
 	s.drawContext.Op = &ebiten.DrawImageOptions{}
-	s.drawContext.Op.GeoM.Scale(3, 3)
-	s.scene.Draw(s.drawContext)
+	s.drawContext.Op.GeoM.Scale(statemachine.Zoom(), statemachine.Zoom())
+	if s.scene != nil {
+		s.scene.Draw(s.drawContext)
+	}
 }
 
 // Layout does a layout.
 func (s *State) Layout(ow, oh int) (int, int) {
 	s.width, s.height = ow, oh
+	s.drawContext.Width = float64(ow)
+	s.drawContext.Height = float64(oh)
 	return ow, oh
 }
@@ -0,0 +1,4 @@
+package post
+
+// lowPower leaves out heavy effects in browsers, where WebGL on weaker GPUs struggles with full-screen passes that sample a lot.
+const lowPower = true
@@ -0,0 +1,6 @@
+//go:build !js
+
+package post
+
+// lowPower leaves out heavy effects. Desktop GPUs can take them.
+const lowPower = false
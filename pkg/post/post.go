@@ -0,0 +1,351 @@
+// Package post runs chains of full-screen Kage shader passes over a finished frame.
+package post
+
+import (
+	_ "embed"
+	"fmt"
+	"image"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/ehh24/pkg/res"
+)
+
+var (
+	//go:embed crt.kage
+	crtSource []byte
+	//go:embed vignette.kage
+	vignetteSource []byte
+	//go:embed grade.kage
+	gradeSource []byte
+	//go:embed aberration.kage
+	aberrationSource []byte
+	//go:embed pixelate.kage
+	pixelateSource []byte
+)
+
+// Enabled turns all post-processing on or off.
+var Enabled = true
+
+// effect is the shader behind an effect kind.
+type effect struct {
+	source   []byte
+	defaults map[string]float64
+	heavy    bool // Samples a lot, so it is left out where GPUs are weak.
+	shader   *ebiten.Shader
+	failed   bool // The shader wouldn't compile, so passes of this kind are skipped.
+}
+
+var effects = map[res.EffectKind]*effect{
+	res.EffectKindCRT: {
+		source:   crtSource,
+		defaults: map[string]float64{"Scanline": 0.25, "Curvature": 0.1},
+		heavy:    true,
+	},
+	res.EffectKindVignette: {
+		source:   vignetteSource,
+		defaults: map[string]float64{"Strength": 0.6, "Radius": 0.75, "Softness": 0.5},
+	},
+	res.EffectKindGrade: {
+		source:   gradeSource,
+		defaults: map[string]float64{"Amount": 1, "Brightness": 1, "Contrast": 1, "Saturation": 1},
+	},
+	res.EffectKindAberration: {
+		source:   aberrationSource,
+		defaults: map[string]float64{"Amount": 0.5},
+		heavy:    true,
+	},
+	res.EffectKindPixelate: {
+		source:   pixelateSource,
+		defaults: map[string]float64{"Size": 1},
+	},
+}
+
+// Params returns the names of an effect kind's params, sorted.
+func Params(kind res.EffectKind) []string {
+	e, ok := effects[kind]
+	if !ok {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(e.defaults))
+}
+
+// Default returns the default value of an effect kind's param.
+func Default(kind res.EffectKind, name string) float64 {
+	if e, ok := effects[kind]; ok {
+		return e.defaults[name]
+	}
+	return 0
+}
+
+// getShader returns the effect's shader, compiling it the first time. Nil is returned if it can't be compiled.
+func (e *effect) getShader() *ebiten.Shader {
+	if e.shader == nil && !e.failed {
+		shader, err := ebiten.NewShader(e.source)
+		if err != nil {
+			fmt.Println("post effect unavailable:", err)
+			e.failed = true
+			return nil
+		}
+		e.shader = shader
+	}
+	return e.shader
+}
+
+// Pass is a single effect in a chain and its current uniforms.
+type Pass struct {
+	Kind   res.EffectKind
+	Params map[string]float64
+	LUT    string
+	tweens map[string]tween
+}
+
+// tween eases a param towards a value over some ticks.
+type tween struct {
+	to    float64
+	ticks int
+}
+
+// Set sets a param immediately.
+func (p *Pass) Set(name string, value float64) {
+	delete(p.tweens, name)
+	p.Params[name] = value
+}
+
+// Tween eases a param to a value over the given number of ticks.
+func (p *Pass) Tween(name string, to float64, ticks int) {
+	if ticks <= 0 {
+		p.Set(name, to)
+		return
+	}
+	p.tweens[name] = tween{to: to, ticks: ticks}
+}
+
+// param returns the current value of a param, being its default if it was never set.
+func (p *Pass) param(name string) float64 {
+	if v, ok := p.Params[name]; ok {
+		return v
+	}
+	return Default(p.Kind, name)
+}
+
+// update steps the pass's tweens along.
+func (p *Pass) update() {
+	for name, t := range p.tweens {
+		from := p.param(name)
+		p.Params[name] = from + (t.to-from)/float64(t.ticks)
+		t.ticks--
+		if t.ticks <= 0 {
+			delete(p.tweens, name)
+		} else {
+			p.tweens[name] = t
+		}
+	}
+}
+
+// Chain is a series of passes, each drawing the result of the last. With no passes, frames are drawn straight to the screen.
+type Chain struct {
+	Passes  []*Pass
+	Scale   float64 // How many screen pixels make up an art pixel.
+	time    float64
+	frames  [2]*ebiten.Image
+	lut     *ebiten.Image // Frame sized, with the LUT in its top left, as shader images must all be the same size.
+	lutSrc  *ebiten.Image // LUT image currently in lut.
+	lutSize float64
+}
+
+// NewChain makes a chain from a place's effects.
+func NewChain(placeEffects []*res.Effect) *Chain {
+	c := &Chain{Scale: 1}
+	for _, e := range placeEffects {
+		p := &Pass{
+			Kind:   e.Kind,
+			Params: make(map[string]float64),
+			LUT:    e.LUT,
+			tweens: make(map[string]tween),
+		}
+		maps.Copy(p.Params, e.Params)
+		c.Passes = append(c.Passes, p)
+	}
+	return c
+}
+
+// Pass returns the first pass of the given kind, or nil.
+func (c *Chain) Pass(kind res.EffectKind) *Pass {
+	for _, p := range c.Passes {
+		if p.Kind == kind {
+			return p
+		}
+	}
+	return nil
+}
+
+// Update steps time and tweens along.
+func (c *Chain) Update() {
+	c.time += 1.0 / 60
+	for _, p := range c.Passes {
+		p.update()
+	}
+}
+
+// active returns the passes that can actually be drawn here.
+func (c *Chain) active() []*Pass {
+	var passes []*Pass
+	for _, p := range c.Passes {
+		e, ok := effects[p.Kind]
+		if !ok || (e.heavy && lowPower) || e.getShader() == nil {
+			continue
+		}
+		passes = append(passes, p)
+	}
+	return passes
+}
+
+// Downscale returns how many screen pixels each pixel of the frame from Begin becomes. It is only more than 1 with a Pixelate pass, whose frames are drawn at art resolution, or lower, to be scaled up by a whole amount.
+func (c *Chain) Downscale() float64 {
+	if !Enabled {
+		return 1
+	}
+	if p := c.pixelate(); p != nil {
+		return math.Max(1, math.Floor(c.Scale*p.param("Size")))
+	}
+	return 1
+}
+
+// pixelate returns the first active Pixelate pass, or nil.
+func (c *Chain) pixelate() *Pass {
+	for _, p := range c.active() {
+		if p.Kind == res.EffectKindPixelate {
+			return p
+		}
+	}
+	return nil
+}
+
+// Begin returns the image to draw the frame to, which is the screen itself if there is nothing to do. It is smaller than the screen by Downscale.
+func (c *Chain) Begin(screen *ebiten.Image) *ebiten.Image {
+	if !Enabled || len(c.active()) == 0 {
+		return screen
+	}
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	for i, f := range c.frames {
+		if f == nil || f.Bounds().Dx() != w || f.Bounds().Dy() != h {
+			if f != nil {
+				f.Dispose()
+			}
+			c.frames[i] = ebiten.NewImage(w, h)
+			c.lutSrc = nil // The LUT canvas has to match too.
+		}
+	}
+	c.frames[0].Clear()
+	return c.frame(0, c.Downscale(), w, h)
+}
+
+// frame returns the top left of a frame, shrunk by down.
+func (c *Chain) frame(i int, down float64, w, h int) *ebiten.Image {
+	return c.frames[i].SubImage(image.Rect(0, 0, int(float64(w)/down), int(float64(h)/down))).(*ebiten.Image)
+}
+
+// End runs the frame from Begin through the passes and onto the screen. If Begin returned the screen, it does nothing.
+func (c *Chain) End(screen, frame *ebiten.Image) {
+	if frame == screen {
+		return
+	}
+	passes := c.active()
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	pixelate := c.pixelate()
+	down := c.Downscale()
+
+	src := frame
+	for i, p := range passes {
+		// Passes up to the Pixelate one work on the small frame, which it then scales up.
+		if p == pixelate {
+			pixelate = nil
+		}
+		dstDown := 1.0
+		if pixelate != nil {
+			dstDown = down
+		}
+		dst := c.frame((i+1)%2, dstDown, w, h)
+		if i == len(passes)-1 {
+			dst = screen
+		} else {
+			dst.Clear()
+		}
+
+		uniforms := map[string]any{
+			"Scale": float32(c.Scale / dstDown),
+			"Time":  float32(c.time),
+		}
+		for name, v := range effects[p.Kind].defaults {
+			uniforms[name] = float32(v)
+		}
+		for name, v := range p.Params {
+			uniforms[name] = float32(v)
+		}
+
+		if p.Kind == res.EffectKindPixelate {
+			c.upscale(dst, src, p, uniforms)
+			src = dst
+			continue
+		}
+
+		dw, dh := dst.Bounds().Dx(), dst.Bounds().Dy()
+		op := &ebiten.DrawRectShaderOptions{}
+		op.Images[0] = src
+		if p.Kind == res.EffectKindGrade {
+			if lut := c.getLUT(p.LUT, w, h); lut != nil {
+				op.Images[1] = lut
+				uniforms["LUTSize"] = float32(c.lutSize)
+			} else {
+				uniforms["LUTSize"] = float32(0)
+			}
+		}
+		op.Uniforms = uniforms
+		dst.DrawRectShader(dw, dh, effects[p.Kind].shader, op)
+		src = dst
+	}
+}
+
+// upscale stretches src over all of dst with a Pixelate pass, each of its pixels becoming a whole number of dst's.
+func (c *Chain) upscale(dst, src *ebiten.Image, p *Pass, uniforms map[string]any) {
+	sb, db := src.Bounds(), dst.Bounds()
+	sx0, sy0, sx1, sy1 := float32(sb.Min.X), float32(sb.Min.Y), float32(sb.Max.X), float32(sb.Max.Y)
+	dx0, dy0 := float32(db.Min.X), float32(db.Min.Y)
+	scale := float32(max(1, db.Dx()/max(1, sb.Dx())))
+	dx1, dy1 := dx0+float32(sb.Dx())*scale, dy0+float32(sb.Dy())*scale
+	vertices := []ebiten.Vertex{
+		{DstX: dx0, DstY: dy0, SrcX: sx0, SrcY: sy0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: dx1, DstY: dy0, SrcX: sx1, SrcY: sy0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: dx0, DstY: dy1, SrcX: sx0, SrcY: sy1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		{DstX: dx1, DstY: dy1, SrcX: sx1, SrcY: sy1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+	}
+	op := &ebiten.DrawTrianglesShaderOptions{}
+	op.Images[0] = src
+	op.Uniforms = uniforms
+	dst.DrawTrianglesShader(vertices, []uint16{0, 1, 2, 1, 3, 2}, effects[p.Kind].shader, op)
+}
+
+// getLUT returns the frame sized canvas holding the named LUT, or nil if there is no such image.
+func (c *Chain) getLUT(name string, w, h int) *ebiten.Image {
+	img, ok := res.Images[name]
+	if !ok {
+		return nil
+	}
+	if c.lut == nil || c.lut.Bounds().Dx() != w || c.lut.Bounds().Dy() != h {
+		if c.lut != nil {
+			c.lut.Dispose()
+		}
+		c.lut = ebiten.NewImage(w, h)
+		c.lutSrc = nil
+	}
+	if c.lutSrc != img {
+		c.lut.Clear()
+		c.lut.DrawImage(img, nil)
+		c.lutSrc = img
+		c.lutSize = float64(img.Bounds().Dy())
+	}
+	return c.lut
+}
@@ -0,0 +1,138 @@
+package render
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/ehh24/pkg/stax"
+)
+
+// Sprite describes a stacked sprite for a Batch to draw.
+type Sprite struct {
+	Image            *ebiten.Image // Image the frame's slices are in.
+	SliceWidth       int
+	SliceHeight      int
+	Frame            *stax.Frame
+	Order            []int // Order to draw the slices in, or bottom to top if nil.
+	X, Y             float64
+	OriginX, OriginY float64 // Offset of the slices, in slice sizes.
+	CenterX, CenterY float64 // Point to rotate around, in slice sizes.
+	Rotation         float64
+	FlipX            bool
+	SliceDistance    float64                        // Vertical distance between stacked slices. Slices are repeated to fill the gaps when scaled up. Zero draws every slice in the same spot.
+	SliceOffset      func(i int) (float64, float64) // Extra offset per slice, such as for eyes or legs.
+	ColorScale       ebiten.ColorScale
+}
+
+// maxVertices is as many vertices as uint16 indices can reach.
+const maxVertices = math.MaxUint16 - 4
+
+// Batch collects the slices of stacked sprites into vertices, drawing them all with as few DrawTriangles calls as possible. A new call is only needed when the target, source image, or blend changes.
+type Batch struct {
+	target   *ebiten.Image
+	image    *ebiten.Image
+	blend    ebiten.Blend
+	vertices []ebiten.Vertex
+	indices  []uint16
+}
+
+// Add queues a sprite to be drawn to target, with geom transforming from world to target coordinates. Slices that land entirely outside of the target are skipped.
+func (b *Batch) Add(target *ebiten.Image, s *Sprite, geom ebiten.GeoM, blend ebiten.Blend) {
+	if s.Frame == nil || s.Image == nil {
+		return
+	}
+	if b.target != target || b.image != s.Image || b.blend != blend {
+		b.Flush()
+		b.target = target
+		b.image = s.Image
+		b.blend = blend
+	}
+
+	repeats := 1
+	if s.SliceDistance > 0 {
+		repeats = int(math.Max(1, s.SliceDistance*geom.Element(0, 0)))
+	}
+
+	w := float64(s.SliceWidth)
+	h := float64(s.SliceHeight)
+	bounds := target.Bounds()
+	cr, cg, cb, ca := s.ColorScale.R(), s.ColorScale.G(), s.ColorScale.B(), s.ColorScale.A()
+
+	var m ebiten.GeoM
+	for n := range s.Frame.Slices {
+		i := n
+		if s.Order != nil {
+			i = s.Order[n]
+		}
+		slice := s.Frame.Slices[i]
+
+		m.Reset()
+		m.Translate(-w*s.CenterX, -h*s.CenterY)
+		m.Rotate(s.Rotation)
+		m.Translate(w*s.CenterX, h*s.CenterY)
+		m.Translate(w*s.OriginX, h*s.OriginY)
+		if s.FlipX {
+			m.Scale(-1, 1)
+		}
+		if s.SliceOffset != nil {
+			m.Translate(s.SliceOffset(i))
+		}
+		m.Translate(s.X, s.Y-s.SliceDistance*float64(i))
+		m.Concat(geom)
+
+		for j := 0; j < repeats; j++ {
+			if len(b.vertices) >= maxVertices {
+				b.Flush()
+			}
+			x0, y0 := m.Apply(0, 0)
+			x1, y1 := m.Apply(w, 0)
+			x2, y2 := m.Apply(0, h)
+			x3, y3 := m.Apply(w, h)
+			y0 += float64(j)
+			y1 += float64(j)
+			y2 += float64(j)
+			y3 += float64(j)
+
+			if math.Max(math.Max(x0, x1), math.Max(x2, x3)) < float64(bounds.Min.X) ||
+				math.Min(math.Min(x0, x1), math.Min(x2, x3)) > float64(bounds.Max.X) ||
+				math.Max(math.Max(y0, y1), math.Max(y2, y3)) < float64(bounds.Min.Y) ||
+				math.Min(math.Min(y0, y1), math.Min(y2, y3)) > float64(bounds.Max.Y) {
+				continue
+			}
+
+			sx0 := float32(slice.X)
+			sy0 := float32(slice.Y)
+			sx1 := sx0 + float32(w)
+			sy1 := sy0 + float32(h)
+
+			base := uint16(len(b.vertices))
+			b.vertices = append(b.vertices,
+				ebiten.Vertex{DstX: float32(x0), DstY: float32(y0), SrcX: sx0, SrcY: sy0, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+				ebiten.Vertex{DstX: float32(x1), DstY: float32(y1), SrcX: sx1, SrcY: sy0, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+				ebiten.Vertex{DstX: float32(x2), DstY: float32(y2), SrcX: sx0, SrcY: sy1, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+				ebiten.Vertex{DstX: float32(x3), DstY: float32(y3), SrcX: sx1, SrcY: sy1, ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca},
+			)
+			b.indices = append(b.indices, base, base+1, base+2, base+1, base+3, base+2)
+		}
+	}
+}
+
+// Flush draws everything queued so far. It must be called before drawing anything else to the same target so that ordering is kept.
+func (b *Batch) Flush() {
+	if len(b.indices) == 0 {
+		return
+	}
+	op := &ebiten.DrawTrianglesOptions{}
+	op.Blend = b.blend
+	op.ColorScaleMode = ebiten.ColorScaleModePremultipliedAlpha
+	b.target.DrawTriangles(b.vertices, b.indices, b.image, op)
+	b.vertices = b.vertices[:0]
+	b.indices = b.indices[:0]
+}
+
+// Draw draws a single sprite immediately, for when there is nothing to batch it with.
+func Draw(target *ebiten.Image, s *Sprite, geom ebiten.GeoM, blend ebiten.Blend) {
+	var b Batch
+	b.Add(target, s, geom, blend)
+	b.Flush()
+}
@@ -0,0 +1,113 @@
+package res
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// AtlasSize is the width and height of each atlas page.
+const AtlasSize = 2048
+
+// atlasPadding is the empty space left around each image to keep filtering from bleeding between neighbors.
+const atlasPadding = 1
+
+// Atlas packs images into a few large pages using shelf packing, so that draws from different images can share a texture.
+type Atlas struct {
+	pages []*atlasPage
+}
+
+type atlasPage struct {
+	img         *ebiten.Image
+	x, y        int // Position of the next image on the current shelf.
+	shelfHeight int
+}
+
+// atlas is our shared atlas for all staxii and images.
+var atlas = &Atlas{}
+
+// Add copies the image into the atlas, returning the page image it is on and the rectangle it occupies. Images too large for a page get a page of their own.
+func (a *Atlas) Add(src image.Image) (*ebiten.Image, image.Rectangle) {
+	w := src.Bounds().Dx()
+	h := src.Bounds().Dy()
+	pw := w + atlasPadding*2
+	ph := h + atlasPadding*2
+
+	if pw > AtlasSize || ph > AtlasSize {
+		return ebiten.NewImageFromImage(src), image.Rect(0, 0, w, h)
+	}
+
+	var page *atlasPage
+	for _, p := range a.pages {
+		if _, _, ok := p.fit(pw, ph); ok {
+			page = p
+			break
+		}
+	}
+	if page == nil {
+		// Pages are left managed so ebiten can restore them if the graphics context is lost, as happens with WebGL.
+		page = &atlasPage{
+			img: ebiten.NewImage(AtlasSize, AtlasSize),
+		}
+		a.pages = append(a.pages, page)
+	}
+	x, y, _ := page.fit(pw, ph)
+	page.place(pw, ph)
+
+	r := image.Rect(x+atlasPadding, y+atlasPadding, x+atlasPadding+w, y+atlasPadding+h)
+	writeImage(page.img.SubImage(r).(*ebiten.Image), src)
+
+	return page.img, r
+}
+
+// writeImage copies the image over dst, which must be the same size.
+func writeImage(dst *ebiten.Image, src image.Image) {
+	// WritePixels wants premultiplied RGBA, which is what draw gives us.
+	rgba := image.NewRGBA(image.Rect(0, 0, src.Bounds().Dx(), src.Bounds().Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, src.Bounds().Min, draw.Src)
+	dst.WritePixels(rgba.Pix)
+}
+
+// Dispose disposes of every page.
+func (a *Atlas) Dispose() {
+	for _, p := range a.pages {
+		p.img.Dispose()
+	}
+	a.pages = nil
+}
+
+// Pages returns the atlas page images.
+func (a *Atlas) Pages() []*ebiten.Image {
+	pages := make([]*ebiten.Image, len(a.pages))
+	for i, p := range a.pages {
+		pages[i] = p.img
+	}
+	return pages
+}
+
+// fit returns where an image of the given size would go, moving to a new shelf if the current one is full.
+func (p *atlasPage) fit(w, h int) (int, int, bool) {
+	x, y := p.x, p.y
+	if x+w > AtlasSize {
+		x = 0
+		y += p.shelfHeight
+	}
+	if y+h > AtlasSize {
+		return 0, 0, false
+	}
+	return x, y, true
+}
+
+// place advances the page past an image of the given size.
+func (p *atlasPage) place(w, h int) {
+	if p.x+w > AtlasSize {
+		p.x = 0
+		p.y += p.shelfHeight
+		p.shelfHeight = 0
+	}
+	p.x += w
+	if h > p.shelfHeight {
+		p.shelfHeight = h
+	}
+}
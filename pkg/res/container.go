@@ -0,0 +1,87 @@
+package res
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Container is somewhere in a place that items are kept, such as a locker. It is opened by using an area whose TargetTag is the container's Tag and whose TargetAction is "open".
+type Container struct {
+	Tag      string
+	Kind     ContainerKind
+	Items    string // IDs of the items it starts with, separated by ';'.
+	Accepts  string // IDs of the only items that can be put in it, separated by ';'. If empty, anything can.
+	Capacity int    // How many slots it has. If 0, it has as many as it needs.
+	// Whenever TargetItem is put in, TargetAction is done to TargetTag as if an area were used.
+	TargetItem   string
+	TargetTag    string
+	TargetAction string
+}
+
+// StartingItems returns the IDs of the items the container starts with.
+func (c *Container) StartingItems() []string {
+	return splitIDs(c.Items)
+}
+
+// AcceptedItems returns the IDs of the only items that can be put in the container, or nil if anything can.
+func (c *Container) AcceptedItems() []string {
+	return splitIDs(c.Accepts)
+}
+
+// splitIDs splits a list of IDs separated by ';', leaving out empty ones.
+func splitIDs(s string) []string {
+	var ids []string
+	for _, id := range strings.Split(s, ";") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// ContainerKind is the kind of a container.
+type ContainerKind int
+
+// Container kinds.
+const (
+	ContainerKindLocker ContainerKind = iota
+	ContainerKindTable
+	ContainerKindTerminal
+)
+
+// ContainerKinds is every container kind, in order.
+var ContainerKinds = []ContainerKind{ContainerKindLocker, ContainerKindTable, ContainerKindTerminal}
+
+// String returns the string representation of a ContainerKind.
+func (k ContainerKind) String() string {
+	switch k {
+	case ContainerKindLocker:
+		return "Locker"
+	case ContainerKindTable:
+		return "Table"
+	case ContainerKindTerminal:
+		return "Terminal"
+	}
+	return "Unknown"
+}
+
+// MarshalText writes the kind by name.
+func (k ContainerKind) MarshalText() ([]byte, error) {
+	for _, kind := range ContainerKinds {
+		if kind == k {
+			return []byte(k.String()), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown container kind %d", k)
+}
+
+// UnmarshalText reads the kind by name.
+func (k *ContainerKind) UnmarshalText(text []byte) error {
+	for _, kind := range ContainerKinds {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown container kind %q", text)
+}
@@ -0,0 +1,63 @@
+package res
+
+import "fmt"
+
+// Effect is a full-screen post-processing pass over the game.
+type Effect struct {
+	Kind   EffectKind
+	Params map[string]float64 // Uniforms of the pass, by name. Any left out use the kind's defaults.
+	LUT    string             // Image to grade colors with, for grade effects. It is a strip of square slices of red by green, one per blue step.
+}
+
+// EffectKind is the kind of post-processing pass.
+type EffectKind int
+
+// Effect kinds.
+const (
+	EffectKindCRT EffectKind = iota
+	EffectKindVignette
+	EffectKindGrade
+	EffectKindAberration
+	EffectKindPixelate
+)
+
+// EffectKinds is every effect kind, in order.
+var EffectKinds = []EffectKind{EffectKindCRT, EffectKindVignette, EffectKindGrade, EffectKindAberration, EffectKindPixelate}
+
+// String returns the string representation of an EffectKind.
+func (k EffectKind) String() string {
+	switch k {
+	case EffectKindCRT:
+		return "CRT"
+	case EffectKindVignette:
+		return "Vignette"
+	case EffectKindGrade:
+		return "Grade"
+	case EffectKindAberration:
+		return "Aberration"
+	case EffectKindPixelate:
+		return "Pixelate"
+	}
+	return "Unknown"
+}
+
+// MarshalText writes the kind by name.
+func (k EffectKind) MarshalText() ([]byte, error) {
+	for _, kind := range EffectKinds {
+		if kind == k {
+			return []byte(k.String()), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown effect kind %d", k)
+}
+
+// UnmarshalText reads the kind by name.
+func (k *EffectKind) UnmarshalText(text []byte) error {
+	for _, kind := range EffectKinds {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown effect kind %q", text)
+}
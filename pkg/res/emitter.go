@@ -0,0 +1,52 @@
+package res
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Emitter is a particle emitter in a place. Particles go off continuously at Rate, and in bursts of Burst whenever the emitter is triggered.
+type Emitter struct {
+	Point          image.Point
+	Tag            string
+	Sprite         string  // Stax or image to draw particles with. If empty or not found, particles are single pixels.
+	Stack          string  // Stack to use, if Sprite is a stax.
+	Animation      string  // Animation to use, if Sprite is a stax.
+	Rate           float64 // Particles per tick while enabled.
+	Burst          int     // Particles emitted at once when triggered.
+	Max            int     // Most particles alive at once.
+	Lifetime       int     // Ticks a particle lives for.
+	LifetimeSpread int     // Random extra ticks a particle may live for.
+	Angle          float64 // Direction particles are launched in, in radians.
+	Spread         float64 // Width of the launch direction, in radians.
+	Speed          float64
+	SpeedSpread    float64 // Random extra speed.
+	Gravity        float64 // Downwards pull, per tick.
+	Drag           float64 // How much velocity is lost per tick, from 0 to 1.
+	StartColor     color.NRGBA
+	EndColor       color.NRGBA
+	StartScale     float64
+	EndScale       float64
+	Layer          StaticLayer
+	Disabled       bool // Doesn't emit at Rate until enabled.
+}
+
+// MakeEmitter makes a small fountain of white sparks that fade out.
+func MakeEmitter() *Emitter {
+	return &Emitter{
+		Rate:        0.5,
+		Burst:       20,
+		Max:         100,
+		Lifetime:    40,
+		Angle:       -math.Pi / 2,
+		Spread:      math.Pi / 4,
+		Speed:       1,
+		SpeedSpread: 0.5,
+		Gravity:     0.05,
+		StartColor:  color.NRGBA{255, 255, 255, 255},
+		EndColor:    color.NRGBA{255, 255, 255, 0},
+		StartScale:  1,
+		EndScale:    1,
+	}
+}
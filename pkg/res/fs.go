@@ -1,12 +1,107 @@
 package res
 
 import (
+	"archive/zip"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"strings"
 )
 
+// Source is a named filesystem that assets can be read from.
+type Source struct {
+	Name string
+	FS   fs.FS
+	Dir  string // On-disk directory of the source, if it has one.
+
+	closer io.Closer // Closes whatever the source holds open, such as a pack's file.
+}
+
+// sources are ordered from lowest to highest priority.
+var sources = baseSources()
+
+// baseSources returns the sources that are always there, before any mods are added.
+func baseSources() []Source {
+	return []Source{
+		{Name: "embedded", FS: f},
+		{Name: "disk", FS: os.DirFS("res"), Dir: "res"},
+	}
+}
+
+// Sources returns the asset sources, from lowest to highest priority.
+func Sources() []Source {
+	return sources
+}
+
+// AddSource adds a source with a higher priority than all existing ones.
+func AddSource(src Source) {
+	sources = append(sources, src)
+}
+
+// AddModDir adds an on-disk directory as a source.
+func AddModDir(dir string) {
+	AddSource(Source{
+		Name: dir,
+		FS:   os.DirFS(dir),
+		Dir:  dir,
+	})
+}
+
+// AddPack adds a zip pack as a source.
+func AddPack(p string) error {
+	z, err := zip.OpenReader(p)
+	if err != nil {
+		return err
+	}
+	AddSource(Source{
+		Name:   p,
+		FS:     z,
+		closer: z,
+	})
+	return nil
+}
+
+// CloseSources closes any open packs, leaving only the base sources.
+func CloseSources() error {
+	var errs []error
+	for _, src := range sources {
+		if src.closer != nil {
+			if err := src.closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", src.Name, err))
+			}
+		}
+	}
+	sources = baseSources()
+	return errors.Join(errs...)
+}
+
+// AddMods adds every directory and zip pack in the given directory as sources, in name order. A missing directory is not an error.
+func AddMods(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	for _, e := range entries {
+		p := filepath.Join(dir, e.Name())
+		if e.IsDir() {
+			AddModDir(p)
+		} else if strings.HasSuffix(e.Name(), ".zip") {
+			if err := AddPack(p); err != nil {
+				return fmt.Errorf("%s: %w", p, err)
+			}
+		}
+	}
+	return nil
+}
+
 // WriteFile writes a file to the disk.
 func WriteFile(name string, data []byte) error {
 	p := filepath.Join("res", name)
@@ -27,51 +122,70 @@ func WriteFile(name string, data []byte) error {
 	return nil
 }
 
-// ReadFile reads a file from the disk, and if that fails, from an embedded resource.
+// ReadFile reads a file from the highest priority source that has it.
 func ReadFile(name string) ([]byte, error) {
-	p := filepath.Join("res", name)
-	file, err := os.Open(p)
-	if err != nil {
-		embed, err := f.ReadFile(name)
-		if err != nil {
-			return nil, err
+	for i := len(sources) - 1; i >= 0; i-- {
+		data, err := fs.ReadFile(sources[i].FS, name)
+		if err == nil {
+			return data, nil
 		}
-		return embed, nil
 	}
-	defer file.Close()
+	return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+}
 
-	data, err := os.ReadFile(p)
-	if err != nil {
-		return nil, err
+// SourceOf returns the name of the highest priority source that has the given file, or an empty string if none do.
+func SourceOf(name string) string {
+	for i := len(sources) - 1; i >= 0; i-- {
+		if _, err := fs.Stat(sources[i].FS, name); err == nil {
+			return sources[i].Name
+		}
 	}
-	return data, nil
+	return ""
 }
 
-// ReadDir reads a directory from the disk, and if that fails, from an embedded directory.
+// ReadDir reads a directory merged across all sources. Entries from higher priority sources replace those of the same name.
 func ReadDir(name string) ([]fs.DirEntry, error) {
-	p := filepath.Join("res", name)
-	entries, err := os.ReadDir(p)
-	if err != nil {
-		fmt.Println(err)
+	var entries []fs.DirEntry
+	found := false
+	for i := len(sources) - 1; i >= 0; i-- {
+		sourceEntries, err := fs.ReadDir(sources[i].FS, name)
+		if err != nil {
+			continue
+		}
+		found = true
+		for _, e := range sourceEntries {
+			if !slices.ContainsFunc(entries, func(e2 fs.DirEntry) bool { return e.Name() == e2.Name() }) {
+				entries = append(entries, e)
+			}
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
 	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries, nil
+}
 
-	embedEntries, err := f.ReadDir(name)
+// ReadDirs recursively reads directory contents across all sources, returning full paths.
+func ReadDirs(name string) ([]string, error) {
+	entries, err := ReadDir(name)
 	if err != nil {
 		return nil, err
 	}
-
-	for _, e := range embedEntries {
-		exists := false
-		for _, e2 := range entries {
-			if e.Name() == e2.Name() {
-				exists = true
+	var names []string
+	for _, e := range entries {
+		p := path.Join(name, e.Name())
+		if e.IsDir() {
+			n2, err := ReadDirs(p)
+			if err != nil {
 				continue
 			}
+			names = append(names, n2...)
+			continue
 		}
-		if !exists {
-			entries = append(entries, e)
-		}
+		names = append(names, p)
 	}
-
-	return entries, nil
+	return names, nil
 }
@@ -0,0 +1,83 @@
+package res
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Item is what a kind of item is, wherever it is kept.
+type Item struct {
+	ID          string // What storage and polygons refer to it by, such as a pickup area's tag or a use area's TargetItem.
+	Name        string // Key of the text shown for it.
+	Description string // Key of the text said when it is examined.
+	Stax        string // Stax it is drawn with.
+	Stackable   bool   // Another of it adds to the count rather than taking up another slot.
+	Combines    []Combination
+}
+
+// Combination is what an item makes when put together with another.
+type Combination struct {
+	With  string // ID of the other item.
+	Makes string // ID of the item made. Both items are used up.
+}
+
+// ItemsFile is the file every item is defined in.
+const ItemsFile = "items.json"
+
+// items is a cache of the defined items, by ID, read from ItemsFile when first needed.
+var items map[string]Item
+
+// Items returns every defined item, by ID, reading them if they have not been yet.
+func Items() map[string]Item {
+	if items != nil {
+		return items
+	}
+	items = make(map[string]Item)
+	if err := readItems(); err != nil {
+		fmt.Println(err)
+	}
+	return items
+}
+
+// readItems reads the item definitions into the cache.
+func readItems() error {
+	data, err := ReadFile(ItemsFile)
+	if err != nil {
+		return err
+	}
+	var defs []Item
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return fmt.Errorf("%s: %w", ItemsFile, err)
+	}
+	for _, def := range defs {
+		items[def.ID] = def
+	}
+	return nil
+}
+
+// GetItem returns the item with the given ID. Items that aren't defined are made up from their ID, so anything with a stax can still be picked up.
+func GetItem(id string) Item {
+	if item, ok := Items()[id]; ok {
+		return item
+	}
+	return Item{
+		ID:   id,
+		Name: id,
+		Stax: id,
+	}
+}
+
+// Combine returns what putting two items together makes, whichever way around they are.
+func Combine(a, b string) (string, bool) {
+	for _, c := range GetItem(a).Combines {
+		if c.With == b {
+			return c.Makes, true
+		}
+	}
+	for _, c := range GetItem(b).Combines {
+		if c.With == a {
+			return c.Makes, true
+		}
+	}
+	return "", false
+}
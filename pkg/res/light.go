@@ -0,0 +1,73 @@
+package res
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// Light is a light source in a place.
+type Light struct {
+	Kind    LightKind
+	Point   image.Point
+	Color   color.NRGBA
+	Radius  float64
+	Angle   float64 // Direction a cone light points, in radians.
+	Spread  float64 // Width of a cone light, in radians.
+	Flicker float64 // How much the light's brightness wavers, from 0 to 1.
+	Tag     string
+}
+
+// MakeLight makes a plain white point light.
+func MakeLight() *Light {
+	return &Light{
+		Kind:   LightKindPoint,
+		Color:  color.NRGBA{255, 255, 255, 255},
+		Radius: 64,
+		Spread: 1,
+	}
+}
+
+// LightKind is the shape of a light.
+type LightKind int
+
+// Light kinds.
+const (
+	LightKindPoint LightKind = iota
+	LightKindCone
+)
+
+// LightKinds is every light kind, in order.
+var LightKinds = []LightKind{LightKindPoint, LightKindCone}
+
+// String returns the string representation of a LightKind.
+func (k LightKind) String() string {
+	switch k {
+	case LightKindPoint:
+		return "Point"
+	case LightKindCone:
+		return "Cone"
+	}
+	return "Unknown"
+}
+
+// MarshalText writes the kind by name.
+func (k LightKind) MarshalText() ([]byte, error) {
+	for _, kind := range LightKinds {
+		if kind == k {
+			return []byte(k.String()), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown light kind %d", k)
+}
+
+// UnmarshalText reads the kind by name.
+func (k *LightKind) UnmarshalText(text []byte) error {
+	for _, kind := range LightKinds {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown light kind %q", text)
+}
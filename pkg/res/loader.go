@@ -0,0 +1,187 @@
+package res
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"path"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/ehh24/pkg/stax"
+)
+
+// Loader loads assets in the background. Files are read and decoded by worker goroutines, while Update must be called from the main thread to turn the results into ebiten images. Places, items, and text are not loaded here, but are instead read on demand by GetPlace, Items, and GetText.
+type Loader struct {
+	total   int
+	done    int
+	results chan loaded
+	err     error
+}
+
+// loaded is the decoded result of a single asset.
+type loaded struct {
+	name   string
+	stax   *stax.Stax
+	img    image.Image
+	script string
+	sound  []byte
+	err    error
+}
+
+// updateBudget is how long Update may spend creating ebiten images per call.
+const updateBudget = 8 * time.Millisecond
+
+// LoadAssets starts loading all assets in the background.
+func LoadAssets() *Loader {
+	l := &Loader{}
+
+	entries, err := ReadDirs(".")
+	if err != nil {
+		l.err = err
+		return l
+	}
+
+	var jobs []string
+	for _, e := range entries {
+		if e == ItemsFile || e == TextFile {
+			// Read on demand, like places.
+			continue
+		} else if strings.HasSuffix(e, ".json") {
+			placeFiles[e[:len(e)-len(".json")]] = e
+		} else if strings.HasSuffix(e, ".png") || strings.HasSuffix(e, ".txt") || IsSound(e) {
+			jobs = append(jobs, e)
+		}
+	}
+	l.total = len(jobs)
+	l.results = make(chan loaded, len(jobs))
+
+	queue := make(chan string, len(jobs))
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+
+	for i := 0; i < runtime.NumCPU(); i++ {
+		go func() {
+			for name := range queue {
+				l.results <- decodeAsset(name)
+			}
+		}()
+	}
+
+	return l
+}
+
+// Update applies decoded assets until they run out or the time budget is spent.
+func (l *Loader) Update() {
+	start := time.Now()
+	for !l.Done() && time.Since(start) < updateBudget {
+		select {
+		case r := <-l.results:
+			l.done++
+			if err := r.apply(); err != nil && l.err == nil {
+				l.err = err
+			}
+		default:
+			if l.done < l.total {
+				// Nothing decoded yet, give the workers a moment.
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}
+}
+
+// Done returns if all assets have been loaded or an error has occurred.
+func (l *Loader) Done() bool {
+	return l.err != nil || l.done >= l.total
+}
+
+// Progress returns the loaded ratio, from 0 to 1.
+func (l *Loader) Progress() float64 {
+	if l.total == 0 {
+		return 1
+	}
+	return float64(l.done) / float64(l.total)
+}
+
+// Err returns the first error encountered while loading.
+func (l *Loader) Err() error {
+	return l.err
+}
+
+// decodeAsset reads and decodes an image, stax, or script. Sounds are just read, as they are decoded by the audio package when played. It is safe to call from any goroutine.
+func decodeAsset(e string) (r loaded) {
+	r.name = e
+	data, err := ReadFile(e)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	if strings.HasSuffix(e, ".png") {
+		st, err := stax.ReadStaxFromPNG(data)
+		if err != nil {
+			fmt.Println(err, "for", e)
+		}
+		r.stax = st
+		if r.img, _, err = image.Decode(bytes.NewReader(data)); err != nil {
+			r.err = fmt.Errorf("%s: %w", e, err)
+		}
+	} else if strings.HasSuffix(e, ".txt") {
+		r.script = string(data)
+	} else if IsSound(e) {
+		r.sound = data
+	}
+	return r
+}
+
+// apply stores the decoded asset in its cache. It must be called from the main thread.
+func (r loaded) apply() error {
+	if r.err != nil {
+		return r.err
+	}
+	if strings.HasSuffix(r.name, ".png") {
+		key := r.name[:len(r.name)-len(".png")]
+		// Reloaded assets of the same size are written over their old space, others are packed again, leaving their old space unused until the next RefreshAssets.
+		page, rect, ok := reusedRegion(key, r.img.Bounds().Size())
+		if ok {
+			writeImage(page.SubImage(rect).(*ebiten.Image), r.img)
+		} else {
+			page, rect = atlas.Add(r.img)
+		}
+		if r.stax == nil {
+			Images[key] = page.SubImage(rect).(*ebiten.Image)
+			delete(Staxii, key)
+		} else {
+			r.stax.Offset(rect.Min.X, rect.Min.Y)
+			Staxii[key] = StaxImage{
+				Stax:     *r.stax,
+				EbiImage: page,
+				Rect:     rect,
+			}
+			delete(Images, key)
+		}
+	} else if strings.HasSuffix(r.name, ".txt") {
+		Scripts[r.name[:len(r.name)-len(".txt")]] = r.script
+	} else if IsSound(r.name) {
+		ext := path.Ext(r.name)
+		Sounds[r.name[:len(r.name)-len(ext)]] = Sound{
+			Data: r.sound,
+			Ogg:  ext == ".ogg",
+		}
+	}
+	return nil
+}
+
+// reusedRegion returns the page and rectangle an already loaded image or stax was packed into, if it is of the given size.
+func reusedRegion(key string, size image.Point) (*ebiten.Image, image.Rectangle, bool) {
+	if st, ok := Staxii[key]; ok && st.Rect.Size() == size {
+		return st.EbiImage, st.Rect, true
+	}
+	if img, ok := Images[key]; ok && img.Bounds().Size() == size {
+		return img, img.Bounds(), true
+	}
+	return nil, image.Rectangle{}, false
+}
@@ -1,36 +1,175 @@
 package res
 
 import (
+	"fmt"
 	"image"
-	"math"
+	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/kettek/ehh24/pkg/render"
+	"github.com/kettek/ehh24/pkg/stax"
 )
 
 // Place is a place in za warudo.
 type Place struct {
-	Name     string
-	Polygons []*Polygon
-	Statics  []*Static
-	Floor    []*Static // We just use Static for floor, however Tag is ignored and Update() is not a thing.
+	Version     int
+	Name        string
+	Polygons    []*Polygon
+	Statics     []*Static
+	Floor       []*Static       // We just use Static for floor, however Tag is ignored and Update() is not a thing.
+	Layers      []*Layer        // Extra decorative layers.
+	Bounds      image.Rectangle // Area the game camera is kept within. If empty, the place's extents are used.
+	Lights      []*Light
+	Ambient     color.NRGBA // Light where no lights reach. If fully transparent, the place is unlit and lights do nothing, unless its view is limited.
+	LimitedView bool        // Limits the game's view to what the player can see, by lighting their view and leaving anything unlit in the dark.
+	Weather     Weather
+	Emitters    []*Emitter
+	Music       string    // Sound to loop as music. Travel crossfades between places' music.
+	Ambience    string    // Sound to loop as ambience, such as the hum of machines or wind.
+	Effects     []*Effect // Post-processing passes, applied in order.
+	Containers  []*Container
+}
+
+// Extents returns the area covered by the place's polygons and the points of its statics and floors.
+func (p *Place) Extents() image.Rectangle {
+	var r image.Rectangle
+	add := func(pt image.Point) {
+		pr := image.Rectangle{Min: pt, Max: pt.Add(image.Pt(1, 1))}
+		if r.Empty() {
+			r = pr
+		} else {
+			r = r.Union(pr)
+		}
+	}
+	for _, poly := range p.Polygons {
+		for _, pt := range poly.Points {
+			add(pt)
+		}
+	}
+	statics := append(append([]*Static{}, p.Statics...), p.Floor...)
+	for _, layer := range p.Layers {
+		statics = append(statics, layer.Statics...)
+	}
+	for _, s := range statics {
+		add(s.Point)
+	}
+	return r
 }
 
 // MakePlace makes a place with a default script.
 func MakePlace() Place {
 	return Place{
-		Name:     "New Place",
-		Polygons: make([]*Polygon, 0),
-		Statics:  make([]*Static, 0),
-		Floor:    make([]*Static, 0),
+		Version:    PlaceVersion,
+		Name:       "New Place",
+		Polygons:   make([]*Polygon, 0),
+		Statics:    make([]*Static, 0),
+		Floor:      make([]*Static, 0),
+		Layers:     make([]*Layer, 0),
+		Lights:     make([]*Light, 0),
+		Emitters:   make([]*Emitter, 0),
+		Effects:    make([]*Effect, 0),
+		Containers: make([]*Container, 0),
+	}
+}
+
+// Layer is a named layer of purely decorative statics.
+type Layer struct {
+	Name    string
+	Opacity float64
+	Statics []*Static
+}
+
+// MakeLayer makes a fully opaque layer.
+func MakeLayer(name string) *Layer {
+	return &Layer{
+		Name:    name,
+		Opacity: 1,
+		Statics: make([]*Static, 0),
 	}
 }
 
 // Static is a stax
 type Static struct {
-	Name  string
-	Point image.Point
-	Tag   string
+	Name      string
+	Point     image.Point
+	Stack     string // Stack to use, or the first if empty.
+	Animation string // Starting animation, or the first if empty.
+	Tag       string
+	Layer     StaticLayer // Draw priority band.
+}
+
+// StaticLayer is the priority band a static is drawn in.
+type StaticLayer int
+
+// Static layers. Default lets the loader decide based on whether it is a floor or a static.
+const (
+	StaticLayerDefault StaticLayer = iota
+	StaticLayerBack
+	StaticLayerMiddle
+	StaticLayerFront
+)
+
+// StaticLayers is every static layer, in order.
+var StaticLayers = []StaticLayer{StaticLayerDefault, StaticLayerBack, StaticLayerMiddle, StaticLayerFront}
+
+// String returns the string representation of a StaticLayer.
+func (l StaticLayer) String() string {
+	switch l {
+	case StaticLayerDefault:
+		return "Default"
+	case StaticLayerBack:
+		return "Back"
+	case StaticLayerMiddle:
+		return "Middle"
+	case StaticLayerFront:
+		return "Front"
+	}
+	return "Unknown"
+}
+
+// MarshalText writes the layer by name.
+func (l StaticLayer) MarshalText() ([]byte, error) {
+	for _, layer := range StaticLayers {
+		if layer == l {
+			return []byte(l.String()), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown static layer %d", l)
+}
+
+// UnmarshalText reads the layer by name.
+func (l *StaticLayer) UnmarshalText(text []byte) error {
+	for _, layer := range StaticLayers {
+		if layer.String() == string(text) {
+			*l = layer
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown static layer %q", text)
+}
+
+// Frame returns the first frame of the static's stack and animation, falling back to the first of each if they cannot be found.
+func (s *Static) Frame(st StaxImage) *stax.Frame {
+	if len(st.Stax.Stacks) == 0 {
+		return nil
+	}
+	stack := &st.Stax.Stacks[0]
+	if s.Stack != "" {
+		if sk := st.Stax.Stack(s.Stack); sk != nil {
+			stack = sk
+		}
+	}
+	if len(stack.Animations) == 0 {
+		return nil
+	}
+	animation := &stack.Animations[0]
+	if s.Animation != "" {
+		if anim := stack.Animation(s.Animation); anim != nil {
+			animation = anim
+		}
+	}
+	return animation.Frame(0)
 }
 
 // Draw draws the static.
@@ -42,34 +181,25 @@ func (s *Static) Draw(screen *ebiten.Image, op *ebiten.DrawImageOptions) {
 		return
 	}
 
-	scale := op.GeoM.Element(0, 0)
-	originX := -0.5
-	originY := -1.0
-
-	const sliceDistance = 1.5
-	sliceDistanceEnd := math.Max(1, sliceDistance*scale)
-
-	opts := &ebiten.DrawImageOptions{}
-	frame := stax.Stax.Stacks[0].Animations[0].Frames[0]
-	for i, slice := range frame.Slices {
-		for j := 0; j < int(sliceDistanceEnd); j++ {
-			opts.GeoM.Reset()
-			//opts.GeoM.Rotate(math.Pi / 30)
-			opts.GeoM.Translate(float64(stax.Stax.SliceWidth)*originX, float64(stax.Stax.SliceHeight)*originY)
-
-			opts.GeoM.Translate(float64(s.Point.X), float64(s.Point.Y))
-
-			opts.GeoM.Translate(0, -sliceDistance*float64(i))
-
-			opts.GeoM.Concat(op.GeoM)
-			opts.ColorScale = op.ColorScale
+	frame := s.Frame(stax)
+	if frame == nil {
+		return
+	}
 
-			opts.GeoM.Translate(0, float64(j))
+	render.Draw(screen, &render.Sprite{
+		Image:         stax.EbiImage,
+		SliceWidth:    stax.Stax.SliceWidth,
+		SliceHeight:   stax.Stax.SliceHeight,
+		Frame:         frame,
+		X:             float64(s.Point.X),
+		Y:             float64(s.Point.Y),
+		OriginX:       -0.5,
+		OriginY:       -1,
+		SliceDistance: 1.5,
+		ColorScale:    op.ColorScale,
+	}, op.GeoM, op.Blend)
 
-			sub := stax.EbiImage.SubImage(image.Rect(slice.X, slice.Y, slice.X+stax.Stax.SliceWidth, slice.Y+stax.Stax.SliceHeight)).(*ebiten.Image)
-			screen.DrawImage(sub, opts)
-		}
-	}
+	scale := op.GeoM.Element(0, 0)
 	if s.Tag != "" {
 		x := int(op.GeoM.Element(0, 2))
 		y := int(op.GeoM.Element(1, 2))
@@ -0,0 +1,135 @@
+package res
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// PlaceVersion is the current version of the place file format.
+//
+//	0: Unversioned. Enums are stored as bare ints.
+//	1: Enums are stored as strings.
+//	2: Travel triggers keep their transition in Transition rather than TargetAction.
+const PlaceVersion = 2
+
+// placeMigration upgrades a raw place from its version to the next one.
+type placeMigration func(raw map[string]any) error
+
+// placeMigrations are indexed by the version they upgrade from.
+var placeMigrations = []placeMigration{
+	migratePlaceV0,
+	migratePlaceV1,
+}
+
+// UnmarshalPlace reads a place, upgrading it from older versions if needed.
+func UnmarshalPlace(data []byte) (Place, error) {
+	var place Place
+
+	raw := make(map[string]any)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return place, err
+	}
+
+	version := 0
+	if v, ok := raw["Version"].(float64); ok {
+		version = int(v)
+	}
+	if version > PlaceVersion {
+		return place, fmt.Errorf("place version %d is newer than supported version %d", version, PlaceVersion)
+	}
+
+	if version < PlaceVersion {
+		for ; version < PlaceVersion; version++ {
+			if err := placeMigrations[version](raw); err != nil {
+				return place, fmt.Errorf("migrating place from version %d: %w", version, err)
+			}
+		}
+		raw["Version"] = PlaceVersion
+		var err error
+		if data, err = json.Marshal(raw); err != nil {
+			return place, err
+		}
+	}
+
+	if err := json.Unmarshal(data, &place); err != nil {
+		return place, err
+	}
+	return place, nil
+}
+
+// MarshalPlace writes a place as indented JSON at the current version.
+func MarshalPlace(place Place) ([]byte, error) {
+	place.Version = PlaceVersion
+	data, err := json.MarshalIndent(place, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// migratePlaceV0 converts the bare int enums to their names. The names are frozen here as they were when the format was unversioned, so reordering consts later does not change what old files mean.
+func migratePlaceV0(raw map[string]any) error {
+	kinds := []string{"None", "Block", "Trigger", "Interact"}
+	subKinds := []string{"Use", "Look", "Pickup", "Travel", "Script", "State"}
+	layers := []string{"Default", "Back", "Middle", "Front"}
+
+	for _, polygon := range rawList(raw["Polygons"]) {
+		if err := rawEnum(polygon, "Kind", kinds); err != nil {
+			return err
+		}
+		if err := rawEnum(polygon, "SubKind", subKinds); err != nil {
+			return err
+		}
+	}
+
+	statics := append(rawList(raw["Statics"]), rawList(raw["Floor"])...)
+	for _, layer := range rawList(raw["Layers"]) {
+		statics = append(statics, rawList(layer["Statics"])...)
+	}
+	for _, static := range statics {
+		if err := rawEnum(static, "Layer", layers); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migratePlaceV1 moves travel triggers' transitions out of TargetAction and into their own field.
+func migratePlaceV1(raw map[string]any) error {
+	for _, polygon := range rawList(raw["Polygons"]) {
+		if polygon["Kind"] != "Trigger" || polygon["SubKind"] != "Travel" {
+			continue
+		}
+		if action, ok := polygon["TargetAction"]; ok {
+			polygon["Transition"] = action
+			delete(polygon, "TargetAction")
+		}
+	}
+	return nil
+}
+
+// rawList returns the objects in a raw JSON array.
+func rawList(v any) []map[string]any {
+	list, _ := v.([]any)
+	var objects []map[string]any
+	for _, item := range list {
+		if o, ok := item.(map[string]any); ok {
+			objects = append(objects, o)
+		}
+	}
+	return objects
+}
+
+// rawEnum replaces a raw int enum field with its name.
+func rawEnum(o map[string]any, field string, names []string) error {
+	v, ok := o[field].(float64)
+	if !ok {
+		return nil
+	}
+	if int(v) < 0 || int(v) >= len(names) {
+		return fmt.Errorf("unknown %s %d", field, int(v))
+	}
+	o[field] = names[int(v)]
+	return nil
+}
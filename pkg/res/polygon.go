@@ -1,6 +1,7 @@
 package res
 
 import (
+	"fmt"
 	"image"
 	"image/color"
 
@@ -21,6 +22,8 @@ type Polygon struct {
 	Script       string // Lookup script name, if applicable
 	Text         string // Text to display, if applicable
 	Disabled     bool
+	// Travel
+	Transition string // Transition to travel with, or the default if empty.
 	// Interact
 	TargetItem string // Item that this polygon uses
 }
@@ -36,6 +39,9 @@ const (
 	PolygonKindInteract
 )
 
+// PolygonKinds is every polygon kind, in order.
+var PolygonKinds = []PolygonKind{PolygonKindNone, PolygonKindBlock, PolygonKindTrigger, PolygonKindInteract}
+
 // String returns the string representation of a PolygonKind.
 func (k PolygonKind) String() string {
 	switch k {
@@ -51,6 +57,27 @@ func (k PolygonKind) String() string {
 	return "Unknown"
 }
 
+// MarshalText writes the kind by name.
+func (k PolygonKind) MarshalText() ([]byte, error) {
+	for _, kind := range PolygonKinds {
+		if kind == k {
+			return []byte(k.String()), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown polygon kind %d", k)
+}
+
+// UnmarshalText reads the kind by name.
+func (k *PolygonKind) UnmarshalText(text []byte) error {
+	for _, kind := range PolygonKinds {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown polygon kind %q", text)
+}
+
 // Color returns the color of a PolygonKind.
 func (k PolygonKind) Color() color.NRGBA {
 	switch k {
@@ -80,6 +107,9 @@ const (
 	PolygonTriggerState
 )
 
+// PolygonSubKinds is every polygon subkind, in order.
+var PolygonSubKinds = []PolygonSubKind{PolygonInteractUse, PolygonInteractLook, PolygonInteractPickup, PolygonTriggerTravel, PolygonTriggerScript, PolygonTriggerState}
+
 // String returns the string representation of a PolygonSubKind.
 func (k PolygonSubKind) String() string {
 	switch k {
@@ -99,11 +129,34 @@ func (k PolygonSubKind) String() string {
 	return ""
 }
 
+// MarshalText writes the subkind by name.
+func (k PolygonSubKind) MarshalText() ([]byte, error) {
+	for _, kind := range PolygonSubKinds {
+		if kind == k {
+			return []byte(k.String()), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown polygon subkind %d", k)
+}
+
+// UnmarshalText reads the subkind by name.
+func (k *PolygonSubKind) UnmarshalText(text []byte) error {
+	for _, kind := range PolygonSubKinds {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown polygon subkind %q", text)
+}
+
 // Draw draws the polygon.
 func (p Polygon) Draw(screen *ebiten.Image, op *ebiten.DrawImageOptions) {
 	scale := float32(op.GeoM.Element(0, 0))
 	x := float32(op.GeoM.Element(0, 2))
 	y := float32(op.GeoM.Element(1, 2))
+	clr := p.Kind.Color()
+	clr.A = uint8(float32(clr.A) * op.ColorScale.A())
 	cx := 0
 	cy := 0
 	for i, pt := range p.Points {
@@ -112,7 +165,7 @@ func (p Polygon) Draw(screen *ebiten.Image, op *ebiten.DrawImageOptions) {
 		if i == 0 {
 			continue
 		}
-		vector.StrokeLine(screen, (float32(p.Points[i-1].X))*scale+x, (float32(p.Points[i-1].Y))*scale+y, (float32(pt.X))*scale+x, (float32(pt.Y))*scale+y, 5, p.Kind.Color(), true)
+		vector.StrokeLine(screen, (float32(p.Points[i-1].X))*scale+x, (float32(p.Points[i-1].Y))*scale+y, (float32(pt.X))*scale+x, (float32(pt.Y))*scale+y, 5, clr, true)
 	}
 	if len(p.Points) > 0 && p.Tag != "" {
 		cx /= len(p.Points)
@@ -2,11 +2,11 @@ package res
 
 import (
 	"embed"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"image"
 	_ "image/png" // I am justifying this as the linter so demands. Look at this justifying, it's unbelievable.
+	"slices"
 	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -16,12 +16,15 @@ import (
 //go:embed *.png
 //go:embed *.json
 //go:embed *.txt
+//go:embed *.cfg
+//go:embed *.wav
 //go:embed nokore.ttf
 var f embed.FS
 
-// StaxImage is a convenience struct that stores Stax and ebiten.Image goodies.
+// StaxImage is a convenience struct that stores Stax and ebiten.Image goodies. EbiImage is the atlas page the stax was packed into, and the stax's slices are positioned within it.
 type StaxImage struct {
 	EbiImage *ebiten.Image
+	Rect     image.Rectangle // Area of EbiImage that the stax occupies.
 	Stax     stax.Stax
 }
 
@@ -31,12 +34,21 @@ var Staxii map[string]StaxImage = make(map[string]StaxImage)
 // Images is a cache of our non-stax images.
 var Images map[string]*ebiten.Image = make(map[string]*ebiten.Image)
 
-// Places is a cache of our places.
-var Places map[string]Place = make(map[string]Place)
+// Sounds is a cache of undecoded sound files, keyed by name without their extension. The audio package decodes them as they are played.
+var Sounds map[string]Sound = make(map[string]Sound)
 
 // Scripts is a cache of place scripts.
 var Scripts map[string]string = make(map[string]string)
 
+// places is a cache of our places, filled in as they are requested.
+var places map[string]Place = make(map[string]Place)
+
+// placeFiles maps place names to the files they are read from.
+var placeFiles map[string]string = make(map[string]string)
+
+// ErrPlaceNotFound is returned when a place does not exist.
+var ErrPlaceNotFound = errors.New("place not found")
+
 // GetStax gets the StaxImage associated with the given name, if possible.
 func GetStax(name string) (StaxImage, error) {
 	st, ok := Staxii[name]
@@ -46,53 +58,62 @@ func GetStax(name string) (StaxImage, error) {
 	return st, nil
 }
 
-// ReadAssets reads all images, staxii, and places from disk.
-func ReadAssets() error {
-	entries, err := ReadDirs(".", "")
+// GetPlace gets the place with the given name, reading it if it has not been yet.
+func GetPlace(name string) (Place, error) {
+	if place, ok := places[name]; ok {
+		return place, nil
+	}
+	file, ok := placeFiles[name]
+	if !ok {
+		return Place{}, ErrPlaceNotFound
+	}
+	data, err := ReadFile(file)
 	if err != nil {
-		return err
+		return Place{}, err
+	}
+	place, err := UnmarshalPlace(data)
+	if err != nil {
+		return Place{}, fmt.Errorf("%s: %w", file, err)
 	}
-	for _, e := range entries {
-		if strings.HasSuffix(e, ".png") {
-			data, err := ReadFile(e)
-			if err != nil {
-				return err
-			}
-			st, err := stax.ReadStaxFromPNG(data)
-			if err != nil {
-				fmt.Println(err, "for", e)
-				//return err
-			}
-			png, _, err := image.Decode(strings.NewReader(string(data)))
-			if err != nil {
-				return err
-			}
-			eimg := ebiten.NewImageFromImage(png)
-			if st == nil {
-				Images[e[:len(e)-len(".png")]] = eimg
-			} else {
-				Staxii[e[:len(e)-len(".png")]] = StaxImage{
-					Stax:     *st,
-					EbiImage: eimg,
-				}
-			}
-		} else if strings.HasSuffix(e, ".json") {
-			data, err := ReadFile(e)
-			if err != nil {
-				return err
-			}
-			var place Place
-			if err := json.Unmarshal(data, &place); err != nil {
-				return err
-			}
-			Places[e[:len(e)-len(".json")]] = place
-		} else if strings.HasSuffix(e, ".txt") {
-			data, err := ReadFile(e)
-			if err != nil {
-				return err
-			}
-			Scripts[e[:len(e)-len(".txt")]] = string(data)
-		}
+	places[name] = place
+	return place, nil
+}
+
+// PlaceNames returns the names of all known places, sorted.
+func PlaceNames() []string {
+	names := make([]string, 0, len(placeFiles))
+	for name := range placeFiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ReadAssets reads all assets, blocking until done. It must be called from the main thread.
+func ReadAssets() error {
+	l := LoadAssets()
+	for !l.Done() {
+		l.Update()
+	}
+	return l.Err()
+}
+
+// ReadAsset reads a single image, stax, sound, place, script, or the items or text into its cache, replacing any existing entry. Other files are ignored. It must be called from the main thread.
+func ReadAsset(e string) error {
+	if e == ItemsFile {
+		items = make(map[string]Item)
+		return readItems()
+	} else if e == TextFile {
+		texts = make(map[string]string)
+		return readTexts()
+	} else if strings.HasSuffix(e, ".json") {
+		key := e[:len(e)-len(".json")]
+		placeFiles[key] = e
+		delete(places, key)
+		_, err := GetPlace(key)
+		return err
+	} else if strings.HasSuffix(e, ".png") || strings.HasSuffix(e, ".txt") || IsSound(e) {
+		return decodeAsset(e).apply()
 	}
 	return nil
 }
@@ -101,28 +122,13 @@ func ReadAssets() error {
 func RefreshAssets() error {
 	Staxii = make(map[string]StaxImage)
 	Images = make(map[string]*ebiten.Image)
-	Places = make(map[string]Place)
 	Scripts = make(map[string]string)
+	Sounds = make(map[string]Sound)
+	places = make(map[string]Place)
+	placeFiles = make(map[string]string)
+	items = nil
+	texts = nil
+	atlas.Dispose()
+	atlas = &Atlas{}
 	return ReadAssets()
 }
-
-// ReadDirs recursively reads directory contents from both embedded and on-disk locations.
-func ReadDirs(name string, prepend string) ([]string, error) {
-	entries, err := ReadDir(name)
-	if err != nil {
-		return nil, err
-	}
-	var names []string
-	for _, e := range entries {
-		if e.IsDir() {
-			n2, err := ReadDirs(e.Name(), e.Name()+"/")
-			if err != nil {
-				continue
-			}
-			names = append(names, n2...)
-			continue
-		}
-		names = append(names, prepend+e.Name())
-	}
-	return names, nil
-}
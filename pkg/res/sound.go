@@ -0,0 +1,14 @@
+package res
+
+import "strings"
+
+// Sound is an undecoded OGG or WAV file.
+type Sound struct {
+	Data []byte
+	Ogg  bool // Otherwise it is a WAV.
+}
+
+// IsSound returns if the file is a sound we can play.
+func IsSound(name string) bool {
+	return strings.HasSuffix(name, ".ogg") || strings.HasSuffix(name, ".wav")
+}
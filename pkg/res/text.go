@@ -0,0 +1,38 @@
+package res
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// TextFile is the file that display text is looked up in.
+const TextFile = "text.json"
+
+// texts is a cache of display text, by key, read from TextFile when first needed.
+var texts map[string]string
+
+// GetText returns the display text for the key. Keys without any text are shown as they are.
+func GetText(key string) string {
+	if texts == nil {
+		texts = make(map[string]string)
+		if err := readTexts(); err != nil {
+			fmt.Println(err)
+		}
+	}
+	if t, ok := texts[key]; ok {
+		return t
+	}
+	return key
+}
+
+// readTexts reads the display text into the cache.
+func readTexts() error {
+	data, err := ReadFile(TextFile)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &texts); err != nil {
+		return fmt.Errorf("%s: %w", TextFile, err)
+	}
+	return nil
+}
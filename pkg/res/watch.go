@@ -0,0 +1,67 @@
+package res
+
+import (
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Watcher polls the on-disk sources for changed assets.
+type Watcher struct {
+	times map[string]time.Time
+}
+
+// NewWatcher creates a watcher, taking note of the current state of the on-disk sources.
+func NewWatcher() *Watcher {
+	w := &Watcher{}
+	w.times = w.scan()
+	return w
+}
+
+// Poll returns the names of any assets that have been changed or added since the last poll.
+func (w *Watcher) Poll() []string {
+	times := w.scan()
+	var changed []string
+	for name, t := range times {
+		if prev, ok := w.times[name]; !ok || !prev.Equal(t) {
+			changed = append(changed, name)
+		}
+	}
+	w.times = times
+	slices.Sort(changed)
+	return changed
+}
+
+// scan collects the modification times of all assets in the on-disk sources. A missing directory, such as in the wasm build, just results in nothing.
+func (w *Watcher) scan() map[string]time.Time {
+	times := make(map[string]time.Time)
+	for _, src := range sources {
+		if src.Dir == "" {
+			continue
+		}
+		filepath.WalkDir(src.Dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			if !strings.HasSuffix(p, ".png") && !strings.HasSuffix(p, ".json") && !strings.HasSuffix(p, ".txt") && !IsSound(p) {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			name, err := filepath.Rel(src.Dir, p)
+			if err != nil {
+				return nil
+			}
+			name = filepath.ToSlash(name)
+			if t, ok := times[name]; !ok || info.ModTime().After(t) {
+				times[name] = info.ModTime()
+			}
+			return nil
+		})
+	}
+	return times
+}
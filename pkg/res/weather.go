@@ -0,0 +1,63 @@
+package res
+
+import "fmt"
+
+// Weather is the weather of a place.
+type Weather struct {
+	Kind      WeatherKind
+	Intensity float64 // From 0 to 1.
+	Wind      float64 // Sideways push, in art pixels per tick.
+}
+
+// WeatherKind is the kind of weather.
+type WeatherKind int
+
+// Weather kinds.
+const (
+	WeatherKindNone WeatherKind = iota
+	WeatherKindSnow
+	WeatherKindRain
+	WeatherKindDust
+	WeatherKindFog
+)
+
+// WeatherKinds is every weather kind, in order.
+var WeatherKinds = []WeatherKind{WeatherKindNone, WeatherKindSnow, WeatherKindRain, WeatherKindDust, WeatherKindFog}
+
+// String returns the string representation of a WeatherKind.
+func (k WeatherKind) String() string {
+	switch k {
+	case WeatherKindNone:
+		return "None"
+	case WeatherKindSnow:
+		return "Snow"
+	case WeatherKindRain:
+		return "Rain"
+	case WeatherKindDust:
+		return "Dust"
+	case WeatherKindFog:
+		return "Fog"
+	}
+	return "Unknown"
+}
+
+// MarshalText writes the kind by name.
+func (k WeatherKind) MarshalText() ([]byte, error) {
+	for _, kind := range WeatherKinds {
+		if kind == k {
+			return []byte(k.String()), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown weather kind %d", k)
+}
+
+// UnmarshalText reads the kind by name.
+func (k *WeatherKind) UnmarshalText(text []byte) error {
+	for _, kind := range WeatherKinds {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown weather kind %q", text)
+}
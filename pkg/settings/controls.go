@@ -0,0 +1,145 @@
+package settings
+
+import (
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/kettek/ehh24/pkg/config"
+	"github.com/kettek/ehh24/pkg/menu"
+	"github.com/kettek/ehh24/pkg/statemachine"
+	input "github.com/quasilyte/ebitengine-input"
+)
+
+// ControlsState lists the bindings and rebinds them. Only the keyboard and mouse keys of a binding are replaced, so gamepads and touch keep working.
+type ControlsState struct {
+	menu     *menu.Menu
+	insys    input.System
+	scanner  *input.KeyScanner
+	scanning string // Name of the binding waiting for a key, if any.
+}
+
+// NewControlsState creates a new state.
+func NewControlsState() *ControlsState {
+	s := &ControlsState{}
+	s.insys.Init(input.SystemConfig{
+		DevicesEnabled: input.AnyDevice,
+	})
+	s.scanner = input.NewKeyScanner(s.insys.NewHandler(0, input.Keymap{}))
+	s.menu = menu.New("Controls")
+	s.menu.Back = func() statemachine.State {
+		return statemachine.Pop(nil)
+	}
+	return s
+}
+
+// Init is called when the state is to be first entered.
+func (s *ControlsState) Init() {
+}
+
+// items returns the menu items, labeled with the current bindings.
+func (s *ControlsState) items() []menu.Item {
+	var items []menu.Item
+	for _, b := range config.Bindings {
+		label := b.Name + ": " + keyNames(config.Keys(b.Name))
+		if b.Name == s.scanning {
+			label = b.Name + ": press a key"
+		}
+		items = append(items, menu.Item{
+			Label: label,
+			Activate: func() statemachine.State {
+				s.scanning = b.Name
+				return nil
+			},
+		})
+	}
+	items = append(items, menu.Item{
+		Label: "Reset",
+		Activate: func() statemachine.State {
+			config.ResetKeys()
+			return nil
+		},
+	}, menu.Item{
+		Label:    "Back",
+		Activate: s.menu.Back,
+	})
+	return items
+}
+
+// keyNames lists the keyboard and mouse keys, leaving out gamepad and touch ones.
+func keyNames(keys []input.Key) string {
+	var names []string
+	for _, k := range keys {
+		if !isFixed(k) {
+			names = append(names, k.String())
+		}
+	}
+	if len(names) == 0 && len(keys) > 0 {
+		return "gamepad/touch only"
+	} else if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// isFixed returns if the key is on a gamepad or touchscreen, which aren't rebound here.
+func isFixed(k input.Key) bool {
+	return strings.HasPrefix(k.String(), "gamepad_") || strings.HasPrefix(k.String(), "touch_")
+}
+
+// mouseKeys are the mouse buttons that can be bound, as the key scanner only reads the keyboard.
+var mouseKeys = map[ebiten.MouseButton]input.Key{
+	ebiten.MouseButtonLeft:   input.KeyMouseLeft,
+	ebiten.MouseButtonRight:  input.KeyMouseRight,
+	ebiten.MouseButtonMiddle: input.KeyMouseMiddle,
+}
+
+// Update updates the state, waiting for a key if rebinding.
+func (s *ControlsState) Update() statemachine.State {
+	s.insys.Update()
+	if s.scanning == "" {
+		s.menu.Items = s.items()
+		return s.menu.Update()
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		s.scanning = ""
+		return nil
+	}
+	for button, k := range mouseKeys {
+		if inpututil.IsMouseButtonJustPressed(button) {
+			s.bind(k)
+			return nil
+		}
+	}
+	if k, status := s.scanner.Scan(); status == input.KeyScanCompleted {
+		s.bind(k)
+	}
+	return nil
+}
+
+// bind replaces the keyboard and mouse keys of the binding being scanned for with the key.
+func (s *ControlsState) bind(k input.Key) {
+	keys := []input.Key{k}
+	for _, old := range config.Keys(s.scanning) {
+		if isFixed(old) {
+			keys = append(keys, old)
+		}
+	}
+	config.SetKeys(s.scanning, keys)
+	s.scanning = ""
+}
+
+// Draw draws the state.
+func (s *ControlsState) Draw(screen *ebiten.Image) {
+	s.menu.Items = s.items()
+	s.menu.Draw(screen)
+}
+
+// Layout does a layout.
+func (s *ControlsState) Layout(ow, oh int) (int, int) {
+	return ow, oh
+}
+
+// Overlay is drawn over the settings.
+func (s *ControlsState) Overlay() {}
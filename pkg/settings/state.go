@@ -0,0 +1,144 @@
+// Package settings is the settings menu, pushed over whatever state wants it. Settings are saved to the config when it is left.
+package settings
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/ehh24/pkg/audio"
+	"github.com/kettek/ehh24/pkg/config"
+	"github.com/kettek/ehh24/pkg/lang"
+	"github.com/kettek/ehh24/pkg/menu"
+	"github.com/kettek/ehh24/pkg/statemachine"
+)
+
+// volumeStep is how much the volume changes per press.
+const volumeStep = 0.1
+
+// textSpeed is a named text speed.
+type textSpeed struct {
+	name  string
+	speed float64
+}
+
+// textSpeeds are the text speeds to pick from, slowest first.
+var textSpeeds = []textSpeed{
+	{"Slow", 0.25},
+	{"Normal", 0.5},
+	{"Fast", 1},
+	{"Instant", 0},
+}
+
+// State be our settings.
+type State struct {
+	menu *menu.Menu
+}
+
+// NewState creates a new state.
+func NewState() *State {
+	s := &State{}
+	s.menu = menu.New("Settings")
+	s.menu.Back = func() statemachine.State {
+		return statemachine.Pop(nil)
+	}
+	return s
+}
+
+// Init is called when the state is to be first entered.
+func (s *State) Init() {
+	ebiten.SetCursorMode(ebiten.CursorModeVisible)
+}
+
+// items returns the menu items, labeled with the current settings.
+func (s *State) items() []menu.Item {
+	var items []menu.Item
+	for _, bus := range audio.Buses {
+		items = append(items, menu.Item{
+			Label: fmt.Sprintf("%s Volume: %d%%", bus, int(math.Round(audio.Volume(bus)*100))),
+			Adjust: func(dir int) {
+				audio.SetVolume(bus, audio.Volume(bus)+volumeStep*float64(dir))
+			},
+		})
+	}
+	items = append(items, menu.Item{
+		Label: "Mute: " + onOff(audio.Muted()),
+		Adjust: func(int) {
+			audio.SetMuted(!audio.Muted())
+		},
+	}, menu.Item{
+		Label: "Fullscreen: " + onOff(ebiten.IsFullscreen()),
+		Adjust: func(int) {
+			ebiten.SetFullscreen(!ebiten.IsFullscreen())
+		},
+	}, menu.Item{
+		Label: "Language: " + lang.Current.String(),
+		Adjust: func(dir int) {
+			i := slices.Index(lang.Languages, lang.Current) + dir
+			lang.Current = lang.Languages[(i+len(lang.Languages))%len(lang.Languages)]
+		},
+	}, menu.Item{
+		Label: "Text Speed: " + textSpeeds[s.textSpeed()].name,
+		Adjust: func(dir int) {
+			i := s.textSpeed() + dir
+			config.Current.TextSpeed = textSpeeds[(i+len(textSpeeds))%len(textSpeeds)].speed
+		},
+	}, menu.Item{
+		Label: "Controls",
+		Activate: func() statemachine.State {
+			return statemachine.Push(NewControlsState(), nil)
+		},
+	}, menu.Item{
+		Label:    "Back",
+		Activate: s.menu.Back,
+	})
+	return items
+}
+
+// textSpeed returns the index of the text speed closest to the config's.
+func (s *State) textSpeed() int {
+	best := 0
+	for i, ts := range textSpeeds {
+		if math.Abs(ts.speed-config.Current.TextSpeed) < math.Abs(textSpeeds[best].speed-config.Current.TextSpeed) {
+			best = i
+		}
+	}
+	return best
+}
+
+// OnExit saves the settings.
+func (s *State) OnExit() {
+	config.Capture()
+	if err := config.Save(); err != nil {
+		fmt.Println("couldn't save settings", err)
+	}
+}
+
+// onOff names a toggle's state.
+func onOff(b bool) string {
+	if b {
+		return "On"
+	}
+	return "Off"
+}
+
+// Update updates the state.
+func (s *State) Update() statemachine.State {
+	s.menu.Items = s.items()
+	return s.menu.Update()
+}
+
+// Draw draws the state.
+func (s *State) Draw(screen *ebiten.Image) {
+	s.menu.Items = s.items()
+	s.menu.Draw(screen)
+}
+
+// Layout does a layout.
+func (s *State) Layout(ow, oh int) (int, int) {
+	return ow, oh
+}
+
+// Overlay is drawn over whatever pushed it.
+func (s *State) Overlay() {}
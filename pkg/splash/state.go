@@ -2,24 +2,26 @@ package splash
 
 import (
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/kettek/ehh24/pkg/intro"
 	"github.com/kettek/ehh24/pkg/res"
 	"github.com/kettek/ehh24/pkg/statemachine"
+	"github.com/kettek/ehh24/pkg/title"
+	"github.com/kettek/ehh24/pkg/transition"
 )
 
+// splashTicks is how long the logo is held for once it has faded in.
+const splashTicks = 60
+
 // State be our intro.
 type State struct {
-	fadeIn  int
-	fadeOut int
-	width   int
-	height  int
+	remaining int
+	width     int
+	height    int
 }
 
 // NewState creates a new state.
 func NewState() *State {
 	return &State{
-		fadeIn:  100,
-		fadeOut: 100,
+		remaining: splashTicks,
 	}
 }
 
@@ -29,30 +31,22 @@ func (s *State) Init() {
 
 // Update updates the state.
 func (s *State) Update() statemachine.State {
-	if s.fadeIn > 0 {
-		s.fadeIn--
-	} else if s.fadeOut > 0 {
-		s.fadeOut--
-	} else {
-		return intro.NewState()
+	if statemachine.Transitioning() {
+		return nil
+	}
+	if s.remaining > 0 {
+		s.remaining--
+		return nil
 	}
-	return nil
+	return statemachine.WithTransition(title.NewState(), transition.New(transition.KindFade, 100))
 }
 
 // Draw draws the state.
 func (s *State) Draw(screen *ebiten.Image) {
 	op := &ebiten.DrawImageOptions{}
-
-	if s.fadeIn > 0 {
-		op.ColorScale.ScaleAlpha(1.0 - float32(s.fadeIn)/100)
-	} else if s.fadeOut > 0 {
-		op.ColorScale.ScaleAlpha(float32(s.fadeOut) / 100)
-	} else {
-		op.ColorScale.ScaleAlpha(0)
-	}
 	{
 		ebi := res.Images["ebiten"]
-		op.GeoM.Translate(float64(s.width)/2-float64(ebi.Bounds().Dy()/2), float64(s.height)/2-float64(ebi.Bounds().Dy())/2)
+		op.GeoM.Translate(float64(s.width)/2-float64(ebi.Bounds().Dx())/2, float64(s.height)/2-float64(ebi.Bounds().Dy())/2)
 		screen.DrawImage(ebi, op)
 	}
 }
@@ -1,52 +1,250 @@
 package statemachine
 
-import "github.com/hajimehoshi/ebiten/v2"
+import (
+	"fmt"
 
-// Machine is the state machine for the game, wow.
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/kettek/ehh24/pkg/transition"
+)
+
+// DefaultTransition makes the transition used when a state changes to another without asking for one.
+func DefaultTransition() *transition.Transition {
+	return transition.New(transition.KindFade, 30)
+}
+
+// transitioning is if the machine is changing states, so states can ignore input until it is done.
+var transitioning bool
+
+// Transitioning returns if the machine is transitioning between states.
+func Transitioning() bool {
+	return transitioning
+}
+
+// Machine is the state machine for the game, wow. States are kept in a stack, and only the top one is updated, so those under it are frozen until it is popped.
 type Machine struct {
-	state  State
+	stack  []State
 	checks []func()
 	w, h   int
+	canvas *ebiten.Image
+	// transition is the one in progress, if any. The old state is frozen until its midpoint, when the new one takes over.
+	transition *transition.Transition
+	quit       bool
 }
 
 // NewMachine does a thing.
 func NewMachine(s State) *Machine {
-	m := &Machine{
-		state: s,
-	}
-	m.state.Init()
+	m := &Machine{}
+	m.enter(s, nil)
 	return m
 }
 
-// Update updates the game state.
+// top returns the state on top of the stack.
+func (g *Machine) top() State {
+	return g.stack[len(g.stack)-1]
+}
+
+// Update updates the top state.
 func (g *Machine) Update() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeyF11) || (inpututil.IsKeyJustPressed(ebiten.KeyEnter) && ebiten.IsKeyPressed(ebiten.KeyAlt)) {
+		ebiten.SetFullscreen(!ebiten.IsFullscreen())
+	}
 	for _, f := range g.checks {
 		f()
 	}
-	next := g.state.Update()
+	if t := g.transition; t != nil {
+		t.Update()
+		if t.Done() {
+			g.transition = nil
+			transitioning = false
+		}
+		if !t.Uncovering() {
+			return nil
+		}
+	}
+	next := g.top().Update()
 	if next != nil {
-		g.SetState(next)
-		g.state = next
+		r := toRequest(next)
+		if r.transition != nil {
+			g.Transition(r, r.transition)
+		} else {
+			g.apply(r)
+		}
+	}
+	if g.quit {
+		return ebiten.Termination
 	}
 	return nil
 }
 
-// Draw draws the game state.
+// Transition carries out the state, or a request from Replace, Push, or Pop, at the midpoint of the transition.
+func (g *Machine) Transition(s State, t *transition.Transition) {
+	r := toRequest(s)
+	mid := t.OnMidpoint
+	t.OnMidpoint = func() {
+		g.apply(r)
+		if mid != nil {
+			mid()
+		}
+	}
+	g.transition = t
+	transitioning = true
+}
+
+// apply does what a request asks.
+func (g *Machine) apply(r *request) {
+	switch r.kind {
+	case requestReplace:
+		g.exit()
+		g.enter(r.State, r.data)
+	case requestPush:
+		if p, ok := g.top().(Pauser); ok {
+			p.OnPause()
+		}
+		g.enter(r.State, r.data)
+	case requestPop:
+		if len(g.stack) == 1 {
+			fmt.Println("can't pop the last state")
+			return
+		}
+		g.exit()
+		if s, ok := g.top().(Resumer); ok {
+			s.OnResume(r.data)
+		}
+		g.layoutState()
+	case requestSet:
+		for len(g.stack) > 0 {
+			g.exit()
+		}
+		g.enter(r.State, r.data)
+	case requestQuit:
+		g.quit = true
+	}
+}
+
+// enter pushes a state and starts it up.
+func (g *Machine) enter(s State, data any) {
+	g.stack = append(g.stack, s)
+	s.Init()
+	if e, ok := s.(Enterer); ok {
+		e.OnEnter(data)
+	}
+	g.layoutState()
+}
+
+// exit pops the top state.
+func (g *Machine) exit() {
+	if e, ok := g.top().(Exiter); ok {
+		e.OnExit()
+	}
+	g.stack = g.stack[:len(g.stack)-1]
+}
+
+// visible returns the states that can be seen, which is the top one and any it is an overlay of.
+func (g *Machine) visible() []State {
+	i := len(g.stack) - 1
+	for i > 0 {
+		if _, ok := g.stack[i].(Overlay); !ok {
+			break
+		}
+		i--
+	}
+	return g.stack[i:]
+}
+
+// Draw draws the visible states from the bottom up, letterboxed into the window if they are scaled.
 func (g *Machine) Draw(screen *ebiten.Image) {
-	g.state.Draw(screen)
+	drawn := false // If the canvas has something to put on the screen.
+	for _, s := range g.visible() {
+		if _, ok := s.(Unscaled); ok {
+			// Unscaled states are left at the window's own resolution, as that is what they were laid out for.
+			g.flush(screen, &drawn)
+			s.Draw(screen)
+			continue
+		}
+		if !drawn {
+			if g.canvas == nil || g.canvas.Bounds().Dx() != current.width || g.canvas.Bounds().Dy() != current.height {
+				if g.canvas != nil {
+					g.canvas.Dispose()
+				}
+				g.canvas = ebiten.NewImage(current.width, current.height)
+			}
+			g.canvas.Clear()
+			drawn = true
+		}
+		s.Draw(g.canvas)
+	}
+
+	if g.transition != nil {
+		if drawn {
+			g.transition.Scale = Zoom()
+			g.transition.Draw(g.canvas)
+		} else {
+			g.transition.Draw(screen)
+		}
+	}
+	g.flush(screen, &drawn)
+}
+
+// flush draws the canvas to the screen if anything has been drawn to it.
+func (g *Machine) flush(screen *ebiten.Image, drawn *bool) {
+	if !*drawn {
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(current.scale, current.scale)
+	op.GeoM.Translate(current.x, current.y)
+	if current.scale != 1 {
+		op.Filter = ebiten.FilterLinear
+	}
+	screen.DrawImage(g.canvas, op)
+	*drawn = false
 }
 
-// Layout does a layout.
+// Layout does a layout. Scaled states are given a canvas of a whole multiple of the logical size, while unscaled ones get the window as is.
 func (g *Machine) Layout(ow, oh int) (int, int) {
 	g.w, g.h = ow, oh
-	return g.state.Layout(ow, oh)
+	return g.layoutState()
+}
+
+// layoutState lays out the states for the last window size. The top state decides if the window is scaled.
+func (g *Machine) layoutState() (int, int) {
+	if g.w == 0 || g.h == 0 {
+		return g.w, g.h
+	}
+	var ow, oh int
+	if _, ok := g.top().(Unscaled); ok {
+		current.unscaled(g.w, g.h)
+		ow, oh = g.w, g.h
+	} else {
+		scale := ebiten.Monitor().DeviceScaleFactor()
+		ww, wh := float64(g.w)*scale, float64(g.h)*scale
+		current.layout(ww, wh)
+		ow, oh = int(ww), int(wh)
+	}
+	for i, s := range g.stack {
+		if _, ok := s.(Unscaled); ok {
+			w, h := s.Layout(g.w, g.h)
+			if i == len(g.stack)-1 {
+				ow, oh = w, h
+			}
+		} else {
+			s.Layout(current.width, current.height)
+		}
+	}
+	return ow, oh
 }
 
-// SetState sets the state.
+// SetState replaces the whole stack with the state immediately.
 func (g *Machine) SetState(s State) {
-	g.state = s
-	g.state.Init()
-	g.state.Layout(g.w, g.h)
+	g.transition = nil
+	transitioning = false
+	g.apply(&request{State: s, kind: requestSet})
+}
+
+// Push pushes a state over the current one immediately, passing it data.
+func (g *Machine) Push(s State, data any) {
+	g.apply(&request{State: s, kind: requestPush, data: data})
 }
 
 // AddCheck adds a check at the beginning of Update.
@@ -1,6 +1,9 @@
 package statemachine
 
-import "github.com/hajimehoshi/ebiten/v2"
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/ehh24/pkg/transition"
+)
 
 // State is the interface for a game state.
 type State interface {
@@ -9,3 +12,82 @@ type State interface {
 	Draw(screen *ebiten.Image)
 	Layout(ow, oh int) (int, int)
 }
+
+// Enterer is implemented by states that want the data they were pushed or changed to with. It is called after Init.
+type Enterer interface {
+	OnEnter(data any)
+}
+
+// Exiter is implemented by states that want to know when they are popped or changed away from.
+type Exiter interface {
+	OnExit()
+}
+
+// Pauser is implemented by states that want to know when another state is pushed over them.
+type Pauser interface {
+	OnPause()
+}
+
+// Resumer is implemented by states that want to know when the state over them is popped, along with what it popped with.
+type Resumer interface {
+	OnResume(data any)
+}
+
+// Overlay is implemented by states that are drawn over the state below them instead of hiding it, such as menus over a frozen game.
+type Overlay interface {
+	Overlay()
+}
+
+// requestKind is what a request asks of the machine.
+type requestKind int
+
+const (
+	requestReplace requestKind = iota // Replace the top state.
+	requestPush                       // Push over the top state.
+	requestPop                        // Pop the top state.
+	requestSet                        // Replace the whole stack.
+	requestQuit                       // Quit the game.
+)
+
+// request is returned from a state's Update in place of a state, to ask for something other than plainly replacing it.
+type request struct {
+	State      // State to change to, if any.
+	kind       requestKind
+	data       any
+	transition *transition.Transition
+}
+
+// Replace replaces the current state with s, passing it data. Returning a plain state from Update does the same without data.
+func Replace(s State, data any) State {
+	return &request{State: s, kind: requestReplace, data: data, transition: DefaultTransition()}
+}
+
+// Push pushes s over the current state, which is paused until s is popped.
+func Push(s State, data any) State {
+	return &request{State: s, kind: requestPush, data: data}
+}
+
+// Pop pops the current state, resuming the one below it with data.
+func Pop(data any) State {
+	return &request{kind: requestPop, data: data}
+}
+
+// Quit quits the game.
+func Quit() State {
+	return &request{kind: requestQuit}
+}
+
+// WithTransition makes the machine carry out a state, or a request from Replace, Push, or Pop, with the given transition.
+func WithTransition(s State, t *transition.Transition) State {
+	r := toRequest(s)
+	r.transition = t
+	return r
+}
+
+// toRequest turns a state returned from Update into a request.
+func toRequest(s State) *request {
+	if r, ok := s.(*request); ok {
+		return r
+	}
+	return &request{State: s, kind: requestReplace, transition: DefaultTransition()}
+}
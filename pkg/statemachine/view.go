@@ -0,0 +1,70 @@
+package statemachine
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// LogicalWidth and LogicalHeight are the size of our view in art pixels. Scaled states are laid out at the largest whole multiple of this that fits the window, and letterboxed within it.
+const (
+	LogicalWidth  = 426
+	LogicalHeight = 240
+)
+
+// Unscaled is implemented by states that want the whole window at its native resolution instead of the letterboxed logical one, such as the editor. They are deliberately drawn straight to the screen without letterboxing or scaling, so they must fit themselves to the window.
+type Unscaled interface {
+	Unscaled()
+}
+
+// view is where the current state's canvas sits within the window.
+type view struct {
+	x, y   float64 // Offset of the canvas within the window.
+	scale  float64 // Scale the canvas is drawn at. This is only not 1 when the window is smaller than the logical size.
+	width  int     // Size of the canvas.
+	height int
+}
+
+var current = view{scale: 1, width: LogicalWidth * 3, height: LogicalHeight * 3}
+
+// layout fits a canvas of the largest whole multiple of the logical size into the given window size.
+func (v *view) layout(ww, wh float64) {
+	zoom := math.Floor(math.Min(ww/LogicalWidth, wh/LogicalHeight))
+	if zoom < 1 {
+		zoom = 1
+	}
+	v.width = LogicalWidth * int(zoom)
+	v.height = LogicalHeight * int(zoom)
+	v.scale = math.Min(1, math.Min(ww/float64(v.width), wh/float64(v.height)))
+	v.x = math.Floor((ww - float64(v.width)*v.scale) / 2)
+	v.y = math.Floor((wh - float64(v.height)*v.scale) / 2)
+}
+
+// unscaled makes the canvas fill the window.
+func (v *view) unscaled(ww, wh int) {
+	v.x, v.y = 0, 0
+	v.scale = 1
+	v.width, v.height = ww, wh
+}
+
+// Size returns the size of the current state's canvas.
+func Size() (int, int) {
+	return current.width, current.height
+}
+
+// Zoom returns how many canvas pixels make up one art pixel.
+func Zoom() float64 {
+	return float64(current.height) / LogicalHeight
+}
+
+// CursorPosition returns the cursor position within the current state's canvas.
+func CursorPosition() (int, int) {
+	x, y := ebiten.CursorPosition()
+	cx, cy := ToCanvas(float64(x), float64(y))
+	return int(cx), int(cy)
+}
+
+// ToCanvas converts a position within the window, such as a touch, to one within the current state's canvas.
+func ToCanvas(x, y float64) (float64, float64) {
+	return (x - current.x) / current.scale, (y - current.y) / current.scale
+}
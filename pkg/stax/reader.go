@@ -46,6 +46,20 @@ func (s *Stax) Stack(name string) *Stack {
 	return nil
 }
 
+// Offset moves every slice by the given amount, such as for when the stax image has been packed into a larger image.
+func (s *Stax) Offset(x, y int) {
+	for i := range s.Stacks {
+		for j := range s.Stacks[i].Animations {
+			for k := range s.Stacks[i].Animations[j].Frames {
+				for l := range s.Stacks[i].Animations[j].Frames[k].Slices {
+					s.Stacks[i].Animations[j].Frames[k].Slices[l].X += x
+					s.Stacks[i].Animations[j].Frames[k].Slices[l].Y += y
+				}
+			}
+		}
+	}
+}
+
 type decodeContext struct {
 	SliceWidth  int
 	SliceHeight int
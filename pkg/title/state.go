@@ -0,0 +1,114 @@
+// Package title is the title menu. It sits at the bottom of the stack, with the game pushed over it, so quitting to it keeps the game around to continue. A game saved from an earlier run can be continued too.
+package title
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kettek/ehh24/pkg/audio"
+	"github.com/kettek/ehh24/pkg/game"
+	"github.com/kettek/ehh24/pkg/intro"
+	"github.com/kettek/ehh24/pkg/menu"
+	"github.com/kettek/ehh24/pkg/outro"
+	"github.com/kettek/ehh24/pkg/settings"
+	"github.com/kettek/ehh24/pkg/statemachine"
+	"github.com/kettek/ehh24/pkg/transition"
+)
+
+// State be our title.
+type State struct {
+	menu      *menu.Menu
+	continued statemachine.State // Game that was quit out of, to continue.
+}
+
+// NewState creates a new state.
+func NewState() *State {
+	s := &State{}
+	s.menu = menu.New("ehh24")
+	s.menu.Backdrop = false
+	s.menu.Items = s.items()
+	return s
+}
+
+// items returns the menu items, with Continue only if there is a game to continue, either quit out of or saved from an earlier run.
+func (s *State) items() []menu.Item {
+	var items []menu.Item
+	hasSave, err := game.HasSave()
+	if err != nil {
+		fmt.Println(err)
+	}
+	if s.continued != nil || hasSave {
+		items = append(items, menu.Item{
+			Label: "Continue",
+			Activate: func() statemachine.State {
+				if s.continued == nil {
+					g, err := game.LoadState()
+					if err != nil {
+						fmt.Println(err)
+						return nil
+					}
+					s.continued = g
+				}
+				return statemachine.WithTransition(statemachine.Push(s.continued, nil), statemachine.DefaultTransition())
+			},
+		})
+	}
+	items = append(items, menu.Item{
+		Label: "New Game",
+		Activate: func() statemachine.State {
+			s.continued = nil
+			return statemachine.WithTransition(statemachine.Push(intro.NewState(), nil), transition.New(transition.KindFade, 60))
+		},
+	}, menu.Item{
+		Label: "Settings",
+		Activate: func() statemachine.State {
+			return statemachine.Push(settings.NewState(), nil)
+		},
+	})
+	// Browsers can't be quit out of.
+	if runtime.GOOS != "js" {
+		items = append(items, menu.Item{
+			Label: "Quit",
+			Activate: func() statemachine.State {
+				return statemachine.WithTransition(statemachine.Quit(), statemachine.DefaultTransition())
+			},
+		})
+	}
+	return items
+}
+
+// Init is called when the state is to be first entered.
+func (s *State) Init() {
+	ebiten.SetCursorMode(ebiten.CursorModeVisible)
+}
+
+// OnResume keeps a game that was quit out of to continue, or forgets it if it was finished.
+func (s *State) OnResume(data any) {
+	ebiten.SetCursorMode(ebiten.CursorModeVisible)
+	switch d := data.(type) {
+	case outro.Finished:
+		s.continued = nil
+	case statemachine.State:
+		s.continued = d
+		audio.PlayMusic("")
+		audio.PlayAmbience("")
+	}
+	s.menu.Items = s.items()
+	s.menu.Selected = 0
+}
+
+// Update updates the state.
+func (s *State) Update() statemachine.State {
+	return s.menu.Update()
+}
+
+// Draw draws the state.
+func (s *State) Draw(screen *ebiten.Image) {
+	s.menu.Draw(screen)
+}
+
+// Layout does a layout.
+func (s *State) Layout(ow, oh int) (int, int) {
+	return ow, oh
+}
@@ -0,0 +1,186 @@
+// Package transition covers the screen between one thing and the next, be it states or places.
+package transition
+
+import (
+	_ "embed"
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+//go:embed transition.kage
+var shaderSource []byte
+
+var (
+	shader *ebiten.Shader
+	failed bool          // The shader wouldn't compile, so everything just fades.
+	pixel  *ebiten.Image // Stretched over the screen for fading without the shader.
+)
+
+// Kind is how a transition covers the screen.
+type Kind int
+
+// Transition kinds.
+const (
+	KindFade Kind = iota
+	KindWipe
+	KindIris
+	KindDissolve
+)
+
+// Kinds is every transition kind, in order.
+var Kinds = []Kind{KindFade, KindWipe, KindIris, KindDissolve}
+
+// String returns the string representation of a Kind.
+func (k Kind) String() string {
+	switch k {
+	case KindFade:
+		return "Fade"
+	case KindWipe:
+		return "Wipe"
+	case KindIris:
+		return "Iris"
+	case KindDissolve:
+		return "Dissolve"
+	}
+	return "Unknown"
+}
+
+// MarshalText writes the kind by name.
+func (k Kind) MarshalText() ([]byte, error) {
+	for _, kind := range Kinds {
+		if kind == k {
+			return []byte(k.String()), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown transition kind %d", k)
+}
+
+// UnmarshalText reads the kind by name.
+func (k *Kind) UnmarshalText(text []byte) error {
+	for _, kind := range Kinds {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown transition kind %q", text)
+}
+
+// Transition covers the screen, calls OnMidpoint once it is fully covered, then uncovers it again.
+type Transition struct {
+	Kind       Kind
+	Duration   int     // Ticks to cover the screen, and again to uncover it.
+	X, Y       float64 // Where an iris closes onto, as a fraction of the screen.
+	Color      color.NRGBA
+	Scale      float64 // Size of dissolve blocks, in screen pixels.
+	OnMidpoint func()
+	OnDone     func()
+	tick       int
+}
+
+// New makes a transition of the given kind taking duration ticks each way.
+func New(kind Kind, duration int) *Transition {
+	return &Transition{
+		Kind:     kind,
+		Duration: max(1, duration),
+		X:        0.5,
+		Y:        0.5,
+		Color:    color.NRGBA{0, 0, 0, 255},
+		Scale:    1,
+	}
+}
+
+// NewIn makes a transition that starts fully covered and only uncovers, so OnMidpoint is never called.
+func NewIn(kind Kind, duration int) *Transition {
+	t := New(kind, duration)
+	t.tick = t.Duration
+	return t
+}
+
+// Update steps the transition along, calling its callbacks as it reaches them.
+func (t *Transition) Update() {
+	if t.Done() {
+		return
+	}
+	t.tick++
+	if t.tick == t.Duration && t.OnMidpoint != nil {
+		t.OnMidpoint()
+	}
+	if t.Done() && t.OnDone != nil {
+		t.OnDone()
+	}
+}
+
+// Done returns if the screen has been uncovered again.
+func (t *Transition) Done() bool {
+	return t.tick >= t.Duration*2
+}
+
+// Uncovering returns if the transition is past its midpoint.
+func (t *Transition) Uncovering() bool {
+	return t.tick >= t.Duration
+}
+
+// Cover returns how much of the screen is covered, going from 0 to 1 and back to 0.
+func (t *Transition) Cover() float64 {
+	if t.tick <= t.Duration {
+		return float64(t.tick) / float64(t.Duration)
+	}
+	return 2 - float64(t.tick)/float64(t.Duration)
+}
+
+// Draw draws the transition over the target.
+func (t *Transition) Draw(target *ebiten.Image) {
+	cover := t.Cover()
+	if cover <= 0 {
+		return
+	}
+	w, h := target.Bounds().Dx(), target.Bounds().Dy()
+	r, g, b, a := t.Color.RGBA()
+
+	s := getShader()
+	if s == nil {
+		// Without the shader, anything is better than nothing, so fade.
+		if pixel == nil {
+			pixel = ebiten.NewImage(1, 1)
+			pixel.Fill(color.White)
+		}
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(float64(w), float64(h))
+		op.ColorScale.ScaleWithColor(t.Color)
+		op.ColorScale.ScaleAlpha(float32(cover))
+		target.DrawImage(pixel, op)
+		return
+	}
+
+	uncovering := float32(0)
+	if t.Uncovering() {
+		uncovering = 1
+	}
+	op := &ebiten.DrawRectShaderOptions{}
+	op.Uniforms = map[string]any{
+		"Kind":       float32(t.Kind),
+		"Cover":      float32(cover),
+		"Uncovering": uncovering,
+		"Center":     []float32{float32(t.X * float64(w)), float32(t.Y * float64(h))},
+		"Color":      []float32{float32(r) / 0xffff, float32(g) / 0xffff, float32(b) / 0xffff, float32(a) / 0xffff},
+		"Scale":      float32(t.Scale),
+	}
+	target.DrawRectShader(w, h, s, op)
+}
+
+// getShader returns the transition shader, compiling it the first time. Nil is returned if it can't be compiled.
+func getShader() *ebiten.Shader {
+	if shader == nil && !failed {
+		s, err := ebiten.NewShader(shaderSource)
+		if err != nil {
+			fmt.Println("transition shader unavailable:", err)
+			failed = true
+			return nil
+		}
+		shader = s
+	}
+	return shader
+}